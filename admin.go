@@ -0,0 +1,213 @@
+package concord
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/ollelogdahl/concord/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// the counters behind Stats, shared by the virtual nodes of a host.
+type counters struct {
+	lookups           atomic.Uint64
+	lookupFailures    atomic.Uint64
+	forwards          atomic.Uint64
+	stabilizeFailures atomic.Uint64
+	suspicions        atomic.Uint64
+	deaths            atomic.Uint64
+}
+
+func (s *counters) snapshot() Stats {
+	return Stats{
+		Lookups:           s.lookups.Load(),
+		LookupFailures:    s.lookupFailures.Load(),
+		Forwards:          s.forwards.Load(),
+		StabilizeFailures: s.stabilizeFailures.Load(),
+		Suspicions:        s.suspicions.Load(),
+		Deaths:            s.deaths.Load(),
+	}
+}
+
+// registers the services of the host; the admin service only if enabled.
+func (h *host) register(srv grpc.ServiceRegistrar) {
+	h.rpc.RegisterService(srv)
+	if h.admin != nil {
+		rpc.RegisterConcordAdminServer(srv, h.admin)
+	}
+}
+
+// serves the ConcordAdmin service, for operators inspecting a running node. It
+// is served next to the ring, by the same transport and TLS settings.
+type adminHandler struct {
+	rpc.UnimplementedConcordAdminServer
+	host *host
+}
+
+// fails requests once the node is stopped, as a server of the caller keeps
+// serving the service.
+func (a *adminHandler) check() error {
+	if !a.host.serving.Load() {
+		return status.Error(codes.Unavailable, "node stopped")
+	}
+	return nil
+}
+
+func (a *adminHandler) GetInfo(ctx context.Context, _ *emptypb.Empty) (*rpc.Info, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+
+	h := a.host
+	first := h.vnodes[0]
+
+	info := &rpc.Info{
+		Name:              first.self.Name,
+		Address:           first.self.Address,
+		HashBits:          uint32(first.hashBits),
+		SuccessorCount:    uint32(first.successorCount),
+		StabilizeInterval: durationpb.New(first.stabilizeInterval),
+		Version:           buildVersion(),
+	}
+
+	h.runLock.Lock()
+	startedAt := h.startedAt
+	h.runLock.Unlock()
+	if s := first.State(); s != StateCreated && s != StateStopped {
+		info.Uptime = durationpb.New(first.clock.Now().Sub(startedAt))
+	}
+
+	for _, v := range h.vnodes {
+		info.VirtualNodes = append(info.VirtualNodes, v.adminInfo())
+	}
+
+	return info, nil
+}
+
+func (a *adminHandler) GetStats(ctx context.Context, _ *emptypb.Empty) (*rpc.Stats, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+
+	s := a.host.vnodes[0].Stats()
+	return &rpc.Stats{
+		Lookups:           s.Lookups,
+		LookupFailures:    s.LookupFailures,
+		Forwards:          s.Forwards,
+		StabilizeFailures: s.StabilizeFailures,
+		Suspicions:        s.Suspicions,
+		Deaths:            s.Deaths,
+		FailureDetector:   s.FailureDetector,
+	}, nil
+}
+
+// runs a round of stabilization on every virtual node, without waiting for the
+// next tick.
+func (a *adminHandler) ForceStabilize(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+
+	for _, v := range a.host.vnodes {
+		if err := ctx.Err(); err != nil {
+			return nil, toStatus(contextError(err))
+		}
+
+		v.lock.RLock()
+		setup, sctx := v.setup, v.stabilizeCtx
+		v.lock.RUnlock()
+		if !setup {
+			return nil, toStatus(ErrNotReady)
+		}
+
+		// the round outlives the request; its notifications run in the background.
+		if !v.stabilizeRound(sctx, true) {
+			return nil, toStatus(ErrNotReady)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// looks up every finger of every virtual node again. Fingers that fail keep
+// their previous node; the errors are reported together.
+func (a *adminHandler) FixAllFingers(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, v := range a.host.vnodes {
+		if !v.ready() {
+			return nil, toStatus(ErrNotReady)
+		}
+		for i := range v.hashBits {
+			if err := v.fixFinger(ctx, i); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// returns the state of a virtual node, as reported by GetInfo.
+func (c *Concord) adminInfo() *rpc.VirtualNode {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	vn := &rpc.VirtualNode{
+		Self:  convertServerToProto(&c.self),
+		Ready: c.setup,
+		Range: convertRangeToProto(c.interval),
+	}
+	if c.predecessor != nil {
+		vn.Predecessor = convertServerToProto(c.predecessor)
+	}
+	for _, s := range c.successors {
+		vn.Successors = append(vn.Successors, convertServerToProto(&s))
+	}
+	for _, f := range c.finger {
+		finger := &rpc.Finger{}
+		finger.Start, finger.WideStart = convertIDToProto(f.Start)
+		if f.Node != nil {
+			finger.Node = convertServerToProto(f.Node)
+		}
+		vn.Fingers = append(vn.Fingers, finger)
+	}
+	return vn
+}
+
+func convertRangeToProto(r Range) *rpc.Range {
+	pr := &rpc.Range{}
+	pr.Start, pr.WideStart = convertIDToProto(r.Start)
+	pr.End, pr.WideEnd = convertIDToProto(r.End)
+	return pr
+}
+
+// returns the version of the concord module the binary was built with.
+func buildVersion() string {
+	const path = "github.com/ollelogdahl/concord"
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if bi.Main.Path == path {
+		return bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == path {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
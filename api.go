@@ -10,13 +10,29 @@ import (
 	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
+// How lookups originating at a node are routed through the ring.
+type LookupMode int
+
+const (
+	// Each hop forwards the request to the next one, and the answer travels
+	// back along the same path.
+	LookupRecursive LookupMode = iota
+	// The originating node asks each hop for the next one and contacts it
+	// itself, routing around hops that fail.
+	LookupIterative
+)
+
 type TLSConfig struct {
 	ServerTLS tls.Config
 	ClientTLS tls.Config
@@ -25,76 +41,238 @@ type TLSConfig struct {
 // The main configuration for the Concord service.
 type Config struct {
 	Name     string
+	ID       *ID
 	BindAddr string
 	AdvAddr  string
 
-	OnRangeChange func(Range)
+	OnRangeChange  func(Range)
+	OnRangesChange func([]Range)
+	OnIsolation    func(isolated bool)
 
 	HashFunc func([]byte) uint64
+	IDFunc   func([]byte) ID
 	HashBits uint
 
 	SuccessorCount uint
+	VirtualNodes   uint
+	MaxHops        uint
+	LookupMode     LookupMode
 	LogHandler     slog.Handler
 
 	StabilizeInterval time.Duration
-
-	TLS *TLSConfig
+	JoinBackoff       time.Duration
+	RPCTimeout        time.Duration
+	RetryPolicy       *RetryPolicy
+	FailureDetector   FailureDetector
+	Clock             Clock
+	RandSource        rand.Source
+
+	TLS                   *TLSConfig
+	GRPCServerOptions     []grpc.ServerOption
+	DialOptions           []grpc.DialOption
+	Transport             Transport
+	MaxConnections        int
+	MaxConnectionFailures uint
+
+	Admin   bool
+	Metrics Metrics
+
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
 }
 
 type Range struct {
-	Start uint64
-	End   uint64
+	Start ID
+	End   ID
 }
 
+// A server on the ring. With virtual nodes, several servers share the Name and
+// Address of one process, and differ only in their Id.
 type Server struct {
 	Name    string
-	Id      uint64
+	Id      ID
 	Address string
 }
 
+// Counters of a process since it was created, summed over its virtual nodes.
+type Stats struct {
+	// Lookups originating at the process, and how many of them failed.
+	Lookups        uint64
+	LookupFailures uint64
+	// Requests sent to other nodes to resolve lookups, by either lookup mode.
+	Forwards uint64
+	// Successors and predecessors found unreachable while stabilizing, and
+	// fingers that could not be fixed.
+	StabilizeFailures uint64
+	// Times a neighbour became suspected, and was declared dead, by the
+	// FailureDetector described.
+	Suspicions      uint64
+	Deaths          uint64
+	FailureDetector string
+}
+
+// The fault tolerance of a node, as of the last probes of its successors.
+type Health struct {
+	// Distinct nodes in the successor list, other than this one, and how many
+	// of them answered their last probe.
+	Successors     int
+	LiveSuccessors int
+	// Simultaneous failures of its successors the node survives without losing
+	// its place in the ring; one fewer than its live successors.
+	FaultTolerance int
+}
+
+// A node that handled a lookup, as reported by LookupTrace.
+type Hop struct {
+	Node Server
+	// Index of the finger the request was forwarded to, or one of HopResolved
+	// and HopSuccessor.
+	Finger int
+	// Round-trip time of forwarding the request to the next hop.
+	Latency time.Duration
+}
+
+const (
+	// The node resolved the lookup itself.
+	HopResolved = -1
+	// The node forwarded the request to its successor list, rather than a finger.
+	HopSuccessor = -2
+)
+
+type findReq struct {
+	id      ID
+	trace   bool
+	hops    uint
+	visited []ID
+}
+
+type findResp struct {
+	server   Server
+	hops     []Hop
+	hopCount uint
+}
+
+// a single step of an iterative lookup, as answered by a hop.
+type lookupStep struct {
+	resolved   bool
+	server     Server
+	finger     int
+	successors []Server
+}
+
 type ring struct {
 	Successors  []Server
 	Predecessor *Server
 }
 
 type fingerEntry struct {
-	Start uint64
+	Start ID
 	Node  *Server
 }
 
+// the server, connections and virtual nodes shared by one process.
+type host struct {
+	bindAddr string
+	// the address advertised as configured, possibly a template, and as
+	// resolved once listening.
+	advertise string
+	advAddr   string
+
+	logHandler slog.Handler
+
+	transport  Transport
+	rpc        *rpcHandler
+	admin      *adminHandler
+	registered grpc.ServiceRegistrar
+	// whether requests are answered; also guards services registered on a
+	// server of the caller, which outlive Stop.
+	serving atomic.Bool
+
+	// serializes Start, Stop, Create, Join and Leave; state is only changed
+	// under it.
+	lifecycle sync.Mutex
+	state     atomic.Int32
+
+	// guards the current run: from Start until Stop.
+	runLock   sync.Mutex
+	startedAt time.Time
+	done      chan struct{}
+	cancelOp  context.CancelFunc
+	// stops waiting for the lifecycle lock; operations started meanwhile are
+	// cancelled at once.
+	stopsWaiting int
+	stopping     bool
+	tasks        sync.WaitGroup
+	pending      map[uint64]Timer
+	nextTask     uint64
+
+	clients     *connectionCache
+	metrics     Metrics
+	tracing     tracing
+	rpcTimeout  time.Duration
+	retryPolicy *RetryPolicy
+	detector    FailureDetector
+
+	// what the virtual nodes have reported to the detector, per peer address.
+	probesLock sync.Mutex
+	probes     map[string]*peerProbes
+
+	vnodes []*Concord
+
+	rngLock sync.Mutex
+	rng     *rand.Rand
+
+	rangesLock           sync.Mutex
+	ranges               map[int]Range
+	rangesChangeCallback func([]Range)
+
+	stats counters
+
+	// peers to rejoin through, and whether the process is cut off from them.
+	peers         peerBook
+	isolationLock sync.Mutex
+	connected     bool
+	isolated      bool
+	onIsolation   func(bool)
+}
+
 // A handle to an instance of the Concord service.
 type Concord struct {
-	self              Server
-	interval          Range
-	successors        []Server
-	predecessor       *Server
+	self        Server
+	interval    Range
+	successors  []Server
+	predecessor *Server
+	// whether each successor answered its last probe.
+	successorAlive map[ID]bool
+	// the predecessor of our predecessor, as last reported by it; takes its
+	// place should it die.
+	secondPredecessor *Server
 	finger            []fingerEntry
 	successorCount    uint
+	maxHops           uint
+	lookupMode        LookupMode
 	stabilizeInterval time.Duration
+	joinBackoff       time.Duration
+	clock             Clock
 
-	bindAddr string
-	advAddr  string
-
-	lock    sync.RWMutex
-	ln      net.Listener
-	srv     *grpc.Server
-	rpc     *rpcHandler
-	started bool
-	setup   bool
+	host  *host
+	vnode int
 
-	clients connectionCache
+	lock  sync.RWMutex
+	setup bool
 
 	stabilizeCtx    context.Context
 	stabilizeCancel context.CancelFunc
+	stabilizeTicker Ticker
+	// held by a round of stabilization, so that rounds never overlap.
+	stabilizing sync.Mutex
 
-	hashFunc func([]byte) uint64
+	hashFunc func([]byte) ID
 	hashBits uint
 
 	rangeChangeCallback func(Range)
 
 	logger *slog.Logger
-
-	clientTLS *tls.Config
 }
 
 // Creates a new instance of the Concord service.
@@ -111,7 +289,7 @@ func (c *Concord) Name() string {
 }
 
 // Returns the ID of the Concord service.
-func (c *Concord) Id() uint64 {
+func (c *Concord) Id() ID {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
@@ -126,63 +304,237 @@ func (c *Concord) Address() string {
 	return c.self.Address
 }
 
-// Starts the Concord service; listens for incoming connections.
+// Starts the Concord service; listens for incoming connections. A stopped
+// service may be started again, and then created or joined anew.
 func (c *Concord) Start() error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	h := c.host
+	return c.start(func() (string, error) {
+		c.logger.Info("starting server", "bind", h.bindAddr)
+		return h.transport.Listen(h.bindAddr, h.register)
+	})
+}
+
+// Starts the Concord service on ln, instead of listening on BindAddr. AdvAddr
+// is filled in from the address of ln, as by Start. Only the default gRPC
+// transport can serve on a listener; Stop closes it.
+func (c *Concord) Serve(ln net.Listener) error {
+	t, ok := c.host.transport.(*grpcTransport)
+	if !ok {
+		return fmt.Errorf("serving on a listener requires the default gRPC transport")
+	}
+	return c.start(func() (string, error) {
+		c.logger.Info("starting server", "listener", ln.Addr())
+		return t.serve(ln, c.host.register)
+	})
+}
 
-	if c.started {
-		return fmt.Errorf("service already started")
+// Registers the Concord services on srv, a gRPC server run by the caller, in
+// place of Start. AdvAddr must be the address srv is reached at; a {host} or
+// {port} in it is filled in from BindAddr. GRPCServerOptions and TLS do not
+// apply to srv. After Stop, srv keeps running, but the services answer every
+// request with Unavailable.
+func (c *Concord) Register(srv grpc.ServiceRegistrar) error {
+	h := c.host
+	return c.start(func() (string, error) {
+		if h.advertise == "" {
+			return "", fmt.Errorf("AdvAddr is required to serve on a server of the caller")
+		}
+		c.logger.Info("registering services on external server")
+		// services stay registered on srv across restarts.
+		if h.registered != srv {
+			h.register(srv)
+			h.registered = srv
+		}
+		return h.bindAddr, nil
+	})
+}
+
+// starts the service, serving through listen; it returns the address served at.
+func (c *Concord) start(listen func() (string, error)) error {
+	h := c.host
+	if err := h.transition("start", StateCreated, StateStopped); err != nil {
+		return err
 	}
-	c.started = true
+	defer h.lifecycle.Unlock()
 
-	c.logger.Info("starting server", "bind", c.bindAddr, "address", c.self.Address)
-	c.rpc.RegisterService(c.srv)
+	bound, err := listen()
+	if err != nil {
+		return err
+	}
 
-	ln, err := net.Listen("tcp", c.bindAddr)
+	addr, err := advertisedAddr(h.advertise, bound)
 	if err != nil {
+		h.transport.Close()
 		return err
 	}
-	c.ln = ln
+	h.setAddress(addr)
 
-	go func() {
-		if err := c.srv.Serve(ln); err != nil {
-			c.logger.Error("failed to serve", "error", err)
-		}
-	}()
+	h.runLock.Lock()
+	if h.stopping {
+		// started again after a stop.
+		h.stopping = false
+		h.done = make(chan struct{})
+	}
+	h.startedAt = c.clock.Now()
+	h.runLock.Unlock()
+
+	h.serving.Store(true)
+	h.state.Store(int32(StateStarted))
 
+	c.logger.Info("listening", "bound", bound, "address", addr)
 	return nil
 }
 
-// Stops the Concord service.
+// Stops the Concord service, without leaving the cluster; to the rest of the
+// ring, it looks like a crash. A create, join or leave in progress is
+// cancelled. Returns once every background task of the service has finished,
+// so it must not be called from a callback of the service, such as
+// OnRangeChange. Stopping a stopped service does nothing.
 func (c *Concord) Stop() error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	if c.setup {
-		c.setup = false
-		c.stabilizeCancel()
+	h := c.host
+	stopped := h.cancelOperations()
+	err := h.transition("stop", StateCreated, StateStarted, StateJoined, StateLeaving)
+	stopped()
+	if err != nil {
+		// already stopped.
+		return nil
 	}
-	if c.started {
-		c.srv.Stop()
-		c.started = false
+	defer h.lifecycle.Unlock()
+
+	c.logger.Info("stopping server")
+
+	for _, v := range h.vnodes {
+		v.stopStabilizing()
 	}
-	return nil
+	h.resetIsolation()
+
+	h.serving.Store(false)
+	err = h.transport.Close()
+	h.stopTasks()
+	h.clients.close()
+
+	h.state.Store(int32(StateStopped))
+	h.runLock.Lock()
+	close(h.done)
+	h.runLock.Unlock()
+
+	return err
 }
 
 // Creates a new cluster. The Concord instance must be started before calling this method.
 func (c *Concord) Create() error {
-	return c.create()
+	h := c.host
+	if err := h.transition("create", StateStarted); err != nil {
+		return err
+	}
+	defer h.lifecycle.Unlock()
+
+	if err := c.create(); err != nil {
+		return err
+	}
+
+	ctx, done := h.operation(context.Background())
+	defer done()
+
+	// virtual nodes join the new ring through ourselves.
+	for _, v := range h.vnodes[1:] {
+		if err := v.join(ctx, []string{h.advAddr}); err != nil {
+			h.resetVnodes()
+			return err
+		}
+	}
+
+	h.state.Store(int32(StateJoined))
+	return nil
 }
 
 // Joins an existing cluster. The Concord instance must be started before calling this method.
 func (c *Concord) Join(ctx context.Context, bootstrapAddress string) error {
-	return c.join(ctx, bootstrapAddress)
+	return c.JoinAny(ctx, []string{bootstrapAddress})
+}
+
+// Joins an existing cluster through any of the given seed addresses. Seeds are
+// tried in a random order, waiting JoinBackoff between rounds, until one succeeds
+// or the context expires. The returned error lists the last failure of each seed.
+func (c *Concord) JoinAny(ctx context.Context, seeds []string) error {
+	h := c.host
+	if err := h.transition("join", StateStarted); err != nil {
+		return err
+	}
+	defer h.lifecycle.Unlock()
+
+	ctx, done := h.operation(ctx)
+	defer done()
+
+	h.peers.addSeeds(seeds)
+	for _, v := range h.vnodes {
+		if err := v.join(ctx, seeds); err != nil {
+			h.resetVnodes()
+			return err
+		}
+	}
+
+	h.state.Store(int32(StateJoined))
+	return nil
+}
+
+// Gracefully leaves the cluster. The successor takes over the range of this
+// node, and the predecessor splices it out of its successor list. Returns once
+// both neighbours have acknowledged. Afterwards, the service is started but
+// not joined, even if leaving failed, and may create or join a cluster again.
+func (c *Concord) Leave(ctx context.Context) error {
+	h := c.host
+	if err := h.transition("leave", StateJoined); err != nil {
+		return err
+	}
+	defer h.lifecycle.Unlock()
+
+	h.state.Store(int32(StateLeaving))
+	defer h.state.Store(int32(StateStarted))
+	defer h.resetIsolation()
+
+	ctx, done := h.operation(ctx)
+	defer done()
+
+	for _, v := range h.vnodes {
+		if err := v.leave(ctx); err != nil {
+			// the rest of the ring notices the others as if they crashed.
+			h.resetVnodes()
+			return err
+		}
+	}
+	return nil
 }
 
 // Looks up the server responsible for the given key.
 func (c *Concord) Lookup(key []byte) (Server, error) {
-	return c.findSuccessor(context.Background(), c.hashFunc(key))
+	return c.LookupContext(context.Background(), key)
+}
+
+// Looks up the server responsible for the given key. The context bounds the
+// whole lookup, including every hop it is forwarded over. Errors can be matched
+// against ErrNotReady, ErrNoRoute, ErrTimeout and ErrAllContendersFailed.
+func (c *Concord) LookupContext(ctx context.Context, key []byte) (Server, error) {
+	return c.findSuccessor(ctx, c.hashFunc(key))
+}
+
+// Looks up the server responsible for the given key, along with the hops the
+// lookup took. The first hop is this node, the last one the node that resolved it.
+// For iterative lookups, the latency of each hop is measured from this node.
+func (c *Concord) LookupTrace(ctx context.Context, key []byte) (Server, []Hop, error) {
+	resp, err := c.route(ctx, findReq{id: c.hashFunc(key), trace: true})
+	if err != nil {
+		return Server{}, nil, err
+	}
+	return resp.server, resp.hops, nil
+}
+
+// Looks up the replica set for the given key: the server responsible for it,
+// followed by its successors. At most n distinct servers are returned; fewer if
+// the ring is small. Virtual nodes of a server already in the set are skipped,
+// so the set is bounded by the SuccessorCount of the owner, less such skips.
+func (c *Concord) LookupReplicas(ctx context.Context, key []byte, n int) ([]Server, error) {
+	return c.findReplicas(ctx, c.hashFunc(key), n)
 }
 
 // Returns the list of successor servers.
@@ -197,6 +549,29 @@ func (c *Concord) Successors() []Server {
 	return copiedSuccessors
 }
 
+// Returns the fault tolerance of this server. Successors are probed every
+// stabilization round, so it lags failures by about StabilizeInterval.
+func (c *Concord) Health() Health {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var h Health
+	seen := map[ID]bool{c.self.Id: true}
+	for _, s := range c.successors {
+		if seen[s.Id] {
+			continue
+		}
+		seen[s.Id] = true
+
+		h.Successors++
+		if c.successorAlive[s.Id] {
+			h.LiveSuccessors++
+		}
+	}
+	h.FaultTolerance = max(h.LiveSuccessors-1, 0)
+	return h
+}
+
 // Returns the predecessor server.
 func (c *Concord) Predecessor() (Server, bool) {
 	c.lock.RLock()
@@ -215,3 +590,28 @@ func (c *Concord) Range() Range {
 
 	return c.interval
 }
+
+// Returns the ranges of keys managed by all virtual nodes of this server.
+func (c *Concord) Ranges() []Range {
+	c.host.rangesLock.Lock()
+	defer c.host.rangesLock.Unlock()
+
+	return c.host.rangesSnapshot()
+}
+
+// Returns the counters of this server, summed over its virtual nodes.
+func (c *Concord) Stats() Stats {
+	s := c.host.stats.snapshot()
+	s.FailureDetector = c.host.detector.String()
+	return s
+}
+
+// Returns the ring positions of all virtual nodes of this server. The first one
+// is the position reported by Id.
+func (c *Concord) VirtualNodes() []Server {
+	servers := make([]Server, len(c.host.vnodes))
+	for i, v := range c.host.vnodes {
+		servers[i] = v.self
+	}
+	return servers
+}
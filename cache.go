@@ -0,0 +1,214 @@
+package concord
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// why a connection was evicted from the cache, as reported to Metrics.
+const (
+	evictedCapacity = "capacity"
+	evictedExpired  = "expired"
+	evictedFailures = "failures"
+)
+
+type cachedConn struct {
+	addr      string
+	conn      ClientConn
+	rpc       rpcClient
+	createdAt time.Time
+	failures  uint
+	// requests in flight on the connection. Once evicted, it is closed when the
+	// last of them finishes.
+	inflight int
+	evicted  bool
+}
+
+// a pool of connections to other nodes, by address. It holds at most maxSize
+// connections, evicting the least recently used one to make room, and drops a
+// connection once it expires or fails maxFailures requests in a row, by being
+// unavailable or timing out. Evicted connections are closed once the requests
+// in flight on them finish.
+type connectionCache struct {
+	mu    sync.Mutex
+	conns map[string]*list.Element
+	// entries ordered by last use, most recent first.
+	lru *list.List
+
+	ttl         time.Duration
+	maxSize     int
+	maxFailures uint
+
+	transport Transport
+	clock     Clock
+	metrics   Metrics
+	// wraps each connection before use, as by host.outbound.
+	wrap func(addr string, conn grpc.ClientConnInterface) grpc.ClientConnInterface
+}
+
+func newConnectionCache(transport Transport, clock Clock, metrics Metrics, ttl time.Duration, maxSize int, maxFailures uint) *connectionCache {
+	return &connectionCache{
+		conns:       make(map[string]*list.Element),
+		lru:         list.New(),
+		ttl:         ttl,
+		maxSize:     maxSize,
+		maxFailures: maxFailures,
+		transport:   transport,
+		clock:       clock,
+		metrics:     metrics,
+		wrap: func(_ string, conn grpc.ClientConnInterface) grpc.ClientConnInterface {
+			return conn
+		},
+	}
+}
+
+func (cc *connectionCache) get(addr string) (rpcClient, error) {
+	cc.mu.Lock()
+	if e, ok := cc.conns[addr]; ok {
+		entry := e.Value.(*cachedConn)
+		if cc.clock.Now().Sub(entry.createdAt) < cc.ttl {
+			cc.lru.MoveToFront(e)
+			cc.mu.Unlock()
+			return entry.rpc, nil
+		}
+		cc.remove(e, evictedExpired)
+	}
+	cc.mu.Unlock()
+
+	cc.metrics.ObserveConnectionMiss()
+	conn, err := cc.transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cachedConn{addr: addr, conn: conn, createdAt: cc.clock.Now()}
+	pooled := &pooledConn{ClientConn: conn, cache: cc, entry: entry}
+	entry.rpc = newClientGrpc(cc.wrap(addr, pooled))
+
+	cc.mu.Lock()
+	// another request may have dialed the same address meanwhile.
+	if e, ok := cc.conns[addr]; ok {
+		cc.lru.MoveToFront(e)
+		existing := e.Value.(*cachedConn)
+		cc.mu.Unlock()
+
+		conn.Close()
+		return existing.rpc, nil
+	}
+
+	cc.conns[addr] = cc.lru.PushFront(entry)
+	for cc.lru.Len() > cc.maxSize {
+		cc.remove(cc.lru.Back(), evictedCapacity)
+	}
+	cc.report()
+	cc.mu.Unlock()
+
+	return entry.rpc, nil
+}
+
+// records a request about to be sent over entry.
+func (cc *connectionCache) begin(entry *cachedConn) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	entry.inflight++
+}
+
+// records the outcome of a request sent over entry with ctx, evicting it once
+// it fails too many requests in a row. A request fails if the peer is
+// unavailable, or does not answer before the request's own timeout; the caller
+// giving up on it is no failure of the peer.
+func (cc *connectionCache) observe(ctx context.Context, entry *cachedConn, err error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	entry.inflight--
+	if entry.evicted {
+		if entry.inflight == 0 {
+			entry.conn.Close()
+		}
+		return
+	}
+
+	timedOut := errors.Is(context.Cause(ctx), errRPCTimeout)
+	if status.Code(err) != codes.Unavailable && !timedOut {
+		entry.failures = 0
+		return
+	}
+
+	entry.failures++
+	if entry.failures < cc.maxFailures {
+		return
+	}
+	// the entry may already have been replaced by a new connection.
+	if e, ok := cc.conns[entry.addr]; ok && e.Value == entry {
+		cc.remove(e, evictedFailures)
+		cc.report()
+	}
+}
+
+// closes and drops every connection. The cache can be used again afterwards.
+func (cc *connectionCache) close() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	for cc.lru.Len() > 0 {
+		e := cc.lru.Front()
+		cc.lru.Remove(e)
+		entry := e.Value.(*cachedConn)
+		delete(cc.conns, entry.addr)
+		entry.conn.Close()
+	}
+	cc.report()
+}
+
+// drops the connection of e, and closes it unless requests are in flight on
+// it; the last of them closes it then, so that they do not fail, and peers are
+// not suspected for an eviction. Must be called with the lock held.
+func (cc *connectionCache) remove(e *list.Element, reason string) {
+	entry := e.Value.(*cachedConn)
+	cc.lru.Remove(e)
+	delete(cc.conns, entry.addr)
+	entry.evicted = true
+	if entry.inflight == 0 {
+		entry.conn.Close()
+	}
+
+	cc.metrics.ObserveConnectionEviction(reason)
+}
+
+// reports the size of the cache, and the states of its connections. Must be
+// called with the lock held.
+func (cc *connectionCache) report() {
+	states := make(map[string]int)
+	for e := cc.lru.Front(); e != nil; e = e.Next() {
+		if sc, ok := e.Value.(*cachedConn).conn.(interface{ GetState() connectivity.State }); ok {
+			states[sc.GetState().String()]++
+		}
+	}
+
+	cc.metrics.SetConnections(cc.lru.Len())
+	cc.metrics.SetConnectionStates(states)
+}
+
+// a connection of the cache, reporting the outcome of each request to it.
+type pooledConn struct {
+	ClientConn
+	cache *connectionCache
+	entry *cachedConn
+}
+
+func (c *pooledConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	c.cache.begin(c.entry)
+	err := c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	c.cache.observe(ctx, c.entry, err)
+	return err
+}
@@ -0,0 +1,91 @@
+package concord
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A source of time for a node. Every timer of the node is scheduled through it,
+// so a simulation can run the node on virtual time. The timeout of each request
+// to another node is the exception, and follows the wall clock.
+type Clock interface {
+	// Returns the current time.
+	Now() time.Time
+	// Calls f every d until the ticker is stopped, in a goroutine of its own.
+	// Calls never overlap; ticks due while f runs are dropped.
+	NewTicker(d time.Duration, f func()) Ticker
+	// Calls f once, after d has passed, in its own goroutine.
+	AfterFunc(d time.Duration, f func()) Timer
+	// Blocks until d has passed, or ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// A pending call scheduled on a Clock.
+type Timer interface {
+	// Prevents the call from running. Returns false if it already ran, or was
+	// stopped before.
+	Stop() bool
+}
+
+// Repeated calls scheduled on a Clock.
+type Ticker interface {
+	// Stops further calls; a call already running is not interrupted.
+	Stop()
+}
+
+// the wall clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration, f func()) Ticker {
+	t := &systemTicker{
+		ticker: time.NewTicker(d),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-t.done:
+				return
+			case <-t.ticker.C:
+				f()
+			}
+		}
+	}()
+
+	return t
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type systemTicker struct {
+	ticker *time.Ticker
+	once   sync.Once
+	done   chan struct{}
+}
+
+func (t *systemTicker) Stop() {
+	t.once.Do(func() {
+		t.ticker.Stop()
+		close(t.done)
+	})
+}
@@ -0,0 +1,346 @@
+package concord
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// The liveness of a peer, as judged by a FailureDetector.
+type PeerStatus int
+
+const (
+	PeerAlive PeerStatus = iota
+	// The peer failed recently, but is given time to recover.
+	PeerSuspected
+	// The peer is considered gone; the ring is repaired around it.
+	PeerDead
+)
+
+func (s PeerStatus) String() string {
+	switch s {
+	case PeerAlive:
+		return "alive"
+	case PeerSuspected:
+		return "suspected"
+	case PeerDead:
+		return "dead"
+	}
+	return fmt.Sprintf("PeerStatus(%d)", int(s))
+}
+
+// Decides when a neighbour that fails to answer is dead, rather than briefly
+// unreachable. A node reports whether each of its neighbours answered to it,
+// once per stabilization round however many of its virtual nodes probed the
+// peer, and only repairs the ring around a peer once it is PeerDead.
+//
+// Peers are identified by address. A FailureDetector is used by a single node,
+// and must be safe for concurrent use. Its String describes its parameters, as
+// reported by Stats.
+type FailureDetector interface {
+	// Records that the peer answered at now.
+	Success(peer string, now time.Time)
+	// Records that a request to the peer failed at now.
+	Failure(peer string, now time.Time)
+	// Returns the status of the peer at now.
+	Status(peer string, now time.Time) PeerStatus
+	// Drops all that is known of the peer.
+	Forget(peer string)
+	String() string
+}
+
+// Judges peers by the number of requests they failed in a row.
+type ThresholdDetector struct {
+	// Failures in a row after which a peer is suspected, and dead. Default to
+	// 1 and 3.
+	Suspect uint
+	Dead    uint
+
+	mu       sync.Mutex
+	failures map[string]uint
+}
+
+func (d *ThresholdDetector) Success(peer string, _ time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.failures, peer)
+}
+
+func (d *ThresholdDetector) Failure(peer string, _ time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.failures == nil {
+		d.failures = make(map[string]uint)
+	}
+	d.failures[peer]++
+}
+
+func (d *ThresholdDetector) Status(peer string, _ time.Time) PeerStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	suspect, dead := d.thresholds()
+	switch n := d.failures[peer]; {
+	case n >= dead:
+		return PeerDead
+	case n >= suspect:
+		return PeerSuspected
+	}
+	return PeerAlive
+}
+
+func (d *ThresholdDetector) Forget(peer string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.failures, peer)
+}
+
+func (d *ThresholdDetector) String() string {
+	suspect, dead := d.thresholds()
+	return fmt.Sprintf("threshold suspect=%d dead=%d", suspect, dead)
+}
+
+func (d *ThresholdDetector) thresholds() (uint, uint) {
+	suspect, dead := d.Suspect, d.Dead
+	if suspect == 0 {
+		suspect = 1
+	}
+	if dead == 0 {
+		dead = 3
+	}
+	return suspect, max(suspect, dead)
+}
+
+// Judges peers by how long they have been silent, compared to the intervals
+// between their past answers; the phi accrual failure detector of Hayashibara
+// et al. Phi is the certainty that a peer is gone: with a phi of 1, the chance
+// that an answer is merely late is 10%, with 8 it is 10^-8.
+type PhiAccrualDetector struct {
+	// Phi at which a peer is suspected, and dead. Default to 1 and 8.
+	SuspectPhi float64
+	DeadPhi    float64
+	// Intervals kept per peer; 100 by default.
+	WindowSize int
+	// Lower bound of the deviation of intervals, so that peers answering at a
+	// very regular pace are not declared dead at once when late; 100ms by
+	// default.
+	MinStdDev time.Duration
+	// Silence tolerated on top of the usual interval before suspicion rises,
+	// as for a peer pausing to collect garbage; 3s by default.
+	AcceptablePause time.Duration
+	// The interval assumed for a peer before it has answered twice; set it to
+	// about the StabilizeInterval. 1s by default.
+	FirstInterval time.Duration
+
+	mu    sync.Mutex
+	peers map[string]*arrivals
+}
+
+// the answers of a peer.
+type arrivals struct {
+	last      time.Time
+	intervals []time.Duration
+	next      int
+}
+
+func (d *PhiAccrualDetector) Success(peer string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.peer(peer, now)
+	if !ok {
+		return
+	}
+
+	interval := now.Sub(a.last)
+	a.last = now
+	if len(a.intervals) < d.windowSize() {
+		a.intervals = append(a.intervals, interval)
+		return
+	}
+	a.intervals[a.next] = interval
+	a.next = (a.next + 1) % len(a.intervals)
+}
+
+func (d *PhiAccrualDetector) Failure(peer string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// silence starts counting from the first time we hear of the peer.
+	d.peer(peer, now)
+}
+
+func (d *PhiAccrualDetector) Status(peer string, now time.Time) PeerStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.peers[peer]
+	if !ok {
+		return PeerAlive
+	}
+
+	suspect, dead := d.thresholds()
+	switch phi := d.phi(a, now); {
+	case phi >= dead:
+		return PeerDead
+	case phi >= suspect:
+		return PeerSuspected
+	}
+	return PeerAlive
+}
+
+func (d *PhiAccrualDetector) Forget(peer string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.peers, peer)
+}
+
+func (d *PhiAccrualDetector) String() string {
+	suspect, dead := d.thresholds()
+	return fmt.Sprintf("phi-accrual suspect=%g dead=%g window=%d min-stddev=%s acceptable-pause=%s first-interval=%s",
+		suspect, dead, d.windowSize(), d.minStdDev(), d.acceptablePause(), d.firstInterval())
+}
+
+// returns the answers of peer, and whether it was known before. Must be called
+// with the lock held.
+func (d *PhiAccrualDetector) peer(peer string, now time.Time) (*arrivals, bool) {
+	if d.peers == nil {
+		d.peers = make(map[string]*arrivals)
+	}
+	a, ok := d.peers[peer]
+	if !ok {
+		a = &arrivals{last: now}
+		d.peers[peer] = a
+	}
+	return a, ok
+}
+
+// returns the suspicion level of a peer silent since a.last, assuming
+// intervals are normally distributed; approximated as by Akka.
+func (d *PhiAccrualDetector) phi(a *arrivals, now time.Time) float64 {
+	mean, stddev := float64(d.firstInterval()), float64(d.firstInterval())/4
+	if n := len(a.intervals); n > 0 {
+		var sum float64
+		for _, i := range a.intervals {
+			sum += float64(i)
+		}
+		mean = sum / float64(n)
+
+		var variance float64
+		for _, i := range a.intervals {
+			variance += (float64(i) - mean) * (float64(i) - mean)
+		}
+		stddev = math.Sqrt(variance / float64(n))
+	}
+	stddev = max(stddev, float64(d.minStdDev()))
+	mean += float64(d.acceptablePause())
+
+	y := (float64(now.Sub(a.last)) - mean) / stddev
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if y > 0 {
+		return -math.Log10(e / (1 + e))
+	}
+	return -math.Log10(1 - 1/(1+e))
+}
+
+func (d *PhiAccrualDetector) thresholds() (float64, float64) {
+	suspect, dead := d.SuspectPhi, d.DeadPhi
+	if suspect == 0 {
+		suspect = 1
+	}
+	if dead == 0 {
+		dead = 8
+	}
+	return suspect, max(suspect, dead)
+}
+
+func (d *PhiAccrualDetector) windowSize() int {
+	if d.WindowSize <= 0 {
+		return 100
+	}
+	return d.WindowSize
+}
+
+func (d *PhiAccrualDetector) minStdDev() time.Duration {
+	if d.MinStdDev <= 0 {
+		return 100 * time.Millisecond
+	}
+	return d.MinStdDev
+}
+
+func (d *PhiAccrualDetector) acceptablePause() time.Duration {
+	if d.AcceptablePause <= 0 {
+		return 3 * time.Second
+	}
+	return d.AcceptablePause
+}
+
+func (d *PhiAccrualDetector) firstInterval() time.Duration {
+	if d.FirstInterval <= 0 {
+		return time.Second
+	}
+	return d.FirstInterval
+}
+
+// what the virtual nodes of a host have reported about a peer.
+type peerProbes struct {
+	// when a probe was last reported to the detector.
+	reported time.Time
+	status   PeerStatus
+}
+
+// reports a probe of a peer to the detector, and returns the status of the
+// peer and whether it changed. Every virtual node probes its neighbours in
+// each round, in several roles, and they share the same processes; so only
+// the first probe of a peer in a round is reported, and a peer is not
+// forgotten when it dies, so that all agree on it.
+func (h *host) probed(peer string, answered bool, now time.Time, round time.Duration) (PeerStatus, bool) {
+	h.probesLock.Lock()
+	defer h.probesLock.Unlock()
+
+	if h.probes == nil {
+		h.probes = make(map[string]*peerProbes)
+	}
+	h.forgetProbes(now, round)
+
+	p, ok := h.probes[peer]
+	if !ok {
+		p = &peerProbes{status: PeerAlive}
+		h.probes[peer] = p
+	}
+
+	// probes less than half a round apart belong to the same round.
+	if !ok || now.Sub(p.reported) >= round/2 {
+		p.reported = now
+		if !answered {
+			h.detector.Failure(peer, now)
+		} else {
+			// a peer coming back from the dead starts with a clean slate.
+			if p.status == PeerDead {
+				h.detector.Forget(peer)
+			}
+			h.detector.Success(peer, now)
+		}
+	}
+
+	status := h.detector.Status(peer, now)
+	changed := status != p.status
+	p.status = status
+	return status, changed
+}
+
+// forgets peers no virtual node has probed in a while, as they are no longer
+// neighbours. Must be called with probesLock held.
+func (h *host) forgetProbes(now time.Time, round time.Duration) {
+	for peer, p := range h.probes {
+		if now.Sub(p.reported) > 10*round {
+			h.detector.Forget(peer)
+			delete(h.probes, peer)
+		}
+	}
+}
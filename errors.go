@@ -0,0 +1,91 @@
+package concord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// The node has not created or joined a cluster yet; retry later.
+	ErrNotReady = errors.New("not ready")
+	// No node could be reached to forward the lookup to.
+	ErrNoRoute = errors.New("no route")
+	// The lookup did not finish before the context deadline.
+	ErrTimeout = errors.New("timeout")
+	// The lookup was forwarded, but every contender failed to resolve it.
+	ErrAllContendersFailed = errors.New("all contenders failed")
+	// The lookup came back to a node it already visited, or ran out of hops.
+	ErrRoutingLoop = errors.New("routing loop")
+	// Another node on the ring already holds the ID of the joining node.
+	ErrIDCollision = errors.New("id collision")
+	// A lifecycle method was called in a state it does not apply to; the error
+	// is a StateError.
+	ErrInvalidState = errors.New("invalid state")
+)
+
+// sentinel errors and the gRPC codes used to carry them across hops.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{ErrNotReady, codes.FailedPrecondition},
+	{ErrNoRoute, codes.NotFound},
+	{ErrTimeout, codes.DeadlineExceeded},
+	{ErrAllContendersFailed, codes.Aborted},
+	{ErrRoutingLoop, codes.ResourceExhausted},
+	{ErrIDCollision, codes.AlreadyExists},
+	{context.Canceled, codes.Canceled},
+}
+
+// an error received from a peer, still matching its sentinel with errors.Is.
+type remoteError struct {
+	sentinel error
+	msg      string
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.sentinel
+}
+
+// converts an error into a gRPC status carrying the code of its sentinel.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return status.Error(ec.code, err.Error())
+		}
+	}
+	return err
+}
+
+// converts a gRPC status received from a peer back into its sentinel error.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, ec := range errorCodes {
+		if st.Code() == ec.code {
+			return &remoteError{sentinel: ec.err, msg: st.Message()}
+		}
+	}
+	return err
+}
+
+// wraps a context error, reporting an expired deadline as ErrTimeout.
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ollelogdahl/concord/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8000", "address of the node to inspect")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [-addr host:port] info|stats|stabilize|fix-fingers\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	admin := rpc.NewConcordAdminClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var resp proto.Message
+	switch flag.Arg(0) {
+	case "info":
+		resp, err = admin.GetInfo(ctx, &emptypb.Empty{})
+	case "stats":
+		resp, err = admin.GetStats(ctx, &emptypb.Empty{})
+	case "stabilize":
+		resp, err = admin.ForceStabilize(ctx, &emptypb.Empty{})
+	case "fix-fingers":
+		resp, err = admin.FixAllFingers(ctx, &emptypb.Empty{})
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(protojson.Format(resp))
+}
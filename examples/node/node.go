@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ollelogdahl/concord"
@@ -15,23 +16,24 @@ func main() {
 	name := flag.String("name", "cord0", "name of the server")
 	bindAddr := flag.String("addr", ":8000", "address to bind to")
 
-	joinAddr := flag.String("join", "", "address of a server to join")
+	joinAddr := flag.String("join", "", "comma-separated addresses of servers to join")
+	admin := flag.Bool("admin", false, "serve the admin service")
 
 	flag.Parse()
-	advAddr := "localhost" + *bindAddr
 
 	// Initialize the server
 	server := concord.New(concord.Config{
 		Name:     *name,
 		BindAddr: *bindAddr,
-		AdvAddr:  advAddr,
+		AdvAddr:  "localhost:{port}",
+		Admin:    *admin,
 	})
 
 	err := server.Start()
 	if err != nil {
 		panic(err)
 	}
-	fmt.Println("Server started at", *bindAddr)
+	fmt.Println("Server started at", server.Address())
 
 	if *joinAddr == "" {
 		server.Create()
@@ -42,7 +44,7 @@ func main() {
 
 		fmt.Println("Joining cluster...")
 
-		err := server.Join(ctx, *joinAddr)
+		err := server.JoinAny(ctx, strings.Split(*joinAddr, ","))
 		if err != nil {
 			panic(err)
 		}
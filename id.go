@@ -0,0 +1,125 @@
+package concord
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// The widest identifier space supported, in bits.
+const MaxHashBits = 256
+
+// An identifier on the ring; an unsigned integer of up to MaxHashBits bits,
+// stored big-endian. IDs compare with == and order with Cmp.
+type ID [MaxHashBits / 8]byte
+
+// Returns the ID with the value v.
+func IDFromUint64(v uint64) ID {
+	var id ID
+	binary.BigEndian.PutUint64(id[len(id)-8:], v)
+	return id
+}
+
+// Returns the ID with the big-endian value b. Only the last MaxHashBits bits
+// of b are used.
+func IDFromBytes(b []byte) ID {
+	var id ID
+	if len(b) > len(id) {
+		b = b[len(b)-len(id):]
+	}
+	copy(id[len(id)-len(b):], b)
+	return id
+}
+
+// Returns the lowest 64 bits of the ID.
+func (id ID) Uint64() uint64 {
+	return binary.BigEndian.Uint64(id[len(id)-8:])
+}
+
+// Returns the big-endian value of the ID, without leading zero bytes.
+func (id ID) Bytes() []byte {
+	return bytes.TrimLeft(id[:], "\x00")
+}
+
+// Returns -1, 0 or 1 if the ID is less than, equal to or greater than o.
+func (id ID) Cmp(o ID) int {
+	return bytes.Compare(id[:], o[:])
+}
+
+// Returns the ID in decimal.
+func (id ID) String() string {
+	return new(big.Int).SetBytes(id[:]).String()
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// parses an ID in decimal, as formatted by String.
+func parseID(s string) (ID, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Sign() < 0 || v.BitLen() > MaxHashBits {
+		return ID{}, fmt.Errorf("invalid id %q", s)
+	}
+	return IDFromBytes(v.Bytes()), nil
+}
+
+// returns whether the ID fits in 64 bits.
+func (id ID) isUint64() bool {
+	for _, b := range id[:len(id)-8] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// returns id + o, modulo 2^MaxHashBits.
+func (id ID) add(o ID) ID {
+	var sum ID
+	carry := 0
+	for i := len(id) - 1; i >= 0; i-- {
+		v := int(id[i]) + int(o[i]) + carry
+		sum[i] = byte(v)
+		carry = v >> 8
+	}
+	return sum
+}
+
+// returns the ID modulo 2^bits.
+func (id ID) mask(bits uint) ID {
+	for i := range id {
+		// bits remaining below this byte.
+		below := uint(len(id)-1-i) * 8
+		switch {
+		case below >= bits:
+			id[i] = 0
+		case below+8 > bits:
+			id[i] &= byte(1<<(bits-below)) - 1
+		}
+	}
+	return id
+}
+
+// returns 2^i.
+func pow2(i uint) ID {
+	var id ID
+	id[len(id)-1-int(i/8)] = 1 << (i % 8)
+	return id
+}
+
+// Returns a hash function mapping data to the first bits bits of its SHA-256
+// digest. For 64 bits, this is the default hash function.
+func SHA256Hash(bits uint) func([]byte) ID {
+	if bits > MaxHashBits {
+		panic(fmt.Sprintf("concord hash-space may not be bigger than %d-bit", MaxHashBits))
+	}
+	return func(data []byte) ID {
+		h := sha256.Sum256(data)
+		v := new(big.Int).SetBytes(h[:])
+		v.Rsh(v, MaxHashBits-bits)
+		return IDFromBytes(v.Bytes())
+	}
+}
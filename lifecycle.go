@@ -0,0 +1,175 @@
+package concord
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// The stage of its lifecycle a node is in. A node is Created by New, Started
+// by Start, Serve or Register, and Joined by Create or Join. Leave takes it
+// back to Started through Leaving, and Stop to Stopped from any state; a
+// stopped node may be started again.
+type State int32
+
+const (
+	StateCreated State = iota
+	StateStarted
+	StateJoined
+	StateLeaving
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StateCreated:
+		return "created"
+	case StateStarted:
+		return "started"
+	case StateJoined:
+		return "joined"
+	case StateLeaving:
+		return "leaving"
+	case StateStopped:
+		return "stopped"
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// Returned by a lifecycle method called in a state it does not apply to, such
+// as Join before Start. Matches ErrInvalidState.
+type StateError struct {
+	Op    string
+	State State
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("cannot %s a node that is %s", e.Op, e.State)
+}
+
+func (e *StateError) Unwrap() error {
+	return ErrInvalidState
+}
+
+// Returns the lifecycle state of the node.
+func (c *Concord) State() State {
+	return State(c.host.state.Load())
+}
+
+// Returns a channel closed once the node is stopped, and its background tasks
+// have finished. A node started again gets a new channel.
+func (c *Concord) Done() <-chan struct{} {
+	h := c.host
+	h.runLock.Lock()
+	defer h.runLock.Unlock()
+
+	return h.done
+}
+
+// takes the lifecycle lock, if the node is in one of the states op applies to.
+// The caller unlocks it.
+func (h *host) transition(op string, from ...State) error {
+	h.lifecycle.Lock()
+
+	s := State(h.state.Load())
+	for _, f := range from {
+		if s == f {
+			return nil
+		}
+	}
+	h.lifecycle.Unlock()
+	return &StateError{Op: op, State: s}
+}
+
+// returns a context for a lifecycle operation that may take long, such as a
+// join, and a function to call once it is over. Stop cancels the context, so
+// it need not wait for the operation to give up.
+func (h *host) operation(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	h.runLock.Lock()
+	h.cancelOp = cancel
+	if h.stopsWaiting > 0 {
+		cancel()
+	}
+	h.runLock.Unlock()
+
+	return ctx, func() {
+		h.runLock.Lock()
+		h.cancelOp = nil
+		h.runLock.Unlock()
+		cancel()
+	}
+}
+
+// cancels the lifecycle operation in progress, if any, and those started until
+// the returned function is called. Stop calls that once it holds the lifecycle
+// lock, since an operation holding the lock may not have registered yet.
+func (h *host) cancelOperations() func() {
+	h.runLock.Lock()
+	defer h.runLock.Unlock()
+
+	h.stopsWaiting++
+	if h.cancelOp != nil {
+		h.cancelOp()
+	}
+	return func() {
+		h.runLock.Lock()
+		h.stopsWaiting--
+		h.runLock.Unlock()
+	}
+}
+
+// registers a background task about to run. Returns false if the host is
+// stopping, and the task should not run; otherwise the caller calls
+// h.tasks.Done once it finishes.
+func (h *host) enter() bool {
+	h.runLock.Lock()
+	defer h.runLock.Unlock()
+
+	if h.stopping {
+		return false
+	}
+	h.tasks.Add(1)
+	return true
+}
+
+// runs f in the background on clock after d, unless the host is stopping.
+func (h *host) spawn(clock Clock, d time.Duration, f func()) {
+	h.runLock.Lock()
+	defer h.runLock.Unlock()
+
+	if h.stopping {
+		return
+	}
+	h.tasks.Add(1)
+
+	// the task waits for the lock, so it is recorded as pending before it
+	// removes itself.
+	id := h.nextTask
+	h.nextTask++
+	h.pending[id] = clock.AfterFunc(d, func() {
+		h.runLock.Lock()
+		delete(h.pending, id)
+		h.runLock.Unlock()
+
+		defer h.tasks.Done()
+		f()
+	})
+}
+
+// refuses new background tasks, cancels those that have not run yet, and
+// waits for the rest to finish.
+func (h *host) stopTasks() {
+	h.runLock.Lock()
+	h.stopping = true
+	for id, t := range h.pending {
+		if t.Stop() {
+			h.tasks.Done()
+		}
+		delete(h.pending, id)
+	}
+	h.runLock.Unlock()
+
+	h.tasks.Wait()
+}
@@ -2,84 +2,213 @@ package concord
 
 import (
 	"context"
-	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math/rand/v2"
+	"net"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 func newConcord(config Config) *Concord {
-	if config.HashFunc == nil {
-		config.HashFunc = func(data []byte) uint64 {
-			h := sha256.Sum256(data)
-			return binary.BigEndian.Uint64(h[:8])
-		}
+	if config.HashBits == 0 {
 		config.HashBits = 64
 	}
 
+	if config.HashBits > MaxHashBits {
+		panic(fmt.Sprintf("concord hash-space may not be bigger than %d-bit", MaxHashBits))
+	}
+
+	// a 64-bit HashFunc is kept for compatibility; IDFunc takes precedence.
+	if config.IDFunc == nil && config.HashFunc != nil {
+		if config.HashBits > 64 {
+			panic("HashFunc only provides 64-bit keys; use IDFunc for wider hash-spaces")
+		}
+		hashFunc := config.HashFunc
+		config.IDFunc = func(data []byte) ID {
+			return IDFromUint64(hashFunc(data))
+		}
+	}
+
+	if config.IDFunc == nil {
+		config.IDFunc = SHA256Hash(config.HashBits)
+	}
+
+	if config.ID != nil && config.ID.mask(config.HashBits) != *config.ID {
+		panic(fmt.Sprintf("concord ID %s does not fit in a %d-bit hash-space", config.ID, config.HashBits))
+	}
+
 	if config.StabilizeInterval == 0 {
 		config.StabilizeInterval = 3 * time.Second
 	}
 
-	id := config.HashFunc([]byte(config.Name))
+	if config.JoinBackoff == 0 {
+		config.JoinBackoff = time.Second
+	}
 
-	cc := &Concord{}
-	cc.self = Server{
-		Name:    config.Name,
-		Id:      id,
-		Address: config.AdvAddr,
+	if config.RPCTimeout == 0 {
+		config.RPCTimeout = 5 * time.Second
+	}
+
+	// a copy, so the defaults are not written into the config of the caller.
+	retry := RetryPolicy{Attempts: 1}
+	if config.RetryPolicy != nil {
+		retry = *config.RetryPolicy
 	}
+	if retry.Attempts == 0 {
+		retry.Attempts = 1
+	}
+	if retry.MaxBackoff == 0 {
+		retry.MaxBackoff = 10 * retry.Backoff
+	}
+	if retry.Retryable == nil {
+		retry.Retryable = retryable
+	}
+	config.RetryPolicy = &retry
 
 	if config.SuccessorCount == 0 {
 		config.SuccessorCount = 3
 	}
-	cc.successorCount = config.SuccessorCount
 
-	var grpcOpts []grpc.ServerOption
+	if config.VirtualNodes == 0 {
+		config.VirtualNodes = 1
+	}
+
+	if config.MaxHops == 0 {
+		config.MaxHops = config.HashBits
+	}
 
-	if config.TLS != nil {
-		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(&config.TLS.ServerTLS)))
-		cc.clientTLS = config.TLS.ClientTLS.Clone()
+	if config.LogHandler == nil {
+		config.LogHandler = slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug})
 	}
 
-	cc.srv = grpc.NewServer(grpcOpts...)
-	cc.rpc = &rpcHandler{concord: cc}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
 
-	if config.HashBits > 64 {
-		panic("concord hash-space may not be bigger than 64-bit")
+	if config.RandSource == nil {
+		config.RandSource = rand.NewPCG(rand.Uint64(), rand.Uint64())
 	}
 
-	cc.hashFunc = config.HashFunc
-	cc.hashBits = config.HashBits
+	if config.MaxConnections == 0 {
+		config.MaxConnections = 64
+	}
 
-	cc.bindAddr = config.BindAddr
-	cc.advAddr = config.AdvAddr
+	if config.MaxConnectionFailures == 0 {
+		config.MaxConnectionFailures = 3
+	}
 
-	cc.rangeChangeCallback = config.OnRangeChange
+	if config.Metrics == nil {
+		config.Metrics = nopMetrics{}
+	}
 
-	if config.LogHandler == nil {
-		config.LogHandler = slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug})
+	if config.FailureDetector == nil {
+		config.FailureDetector = &ThresholdDetector{}
 	}
 
-	cc.logger = slog.New(config.LogHandler).With(
-		"name", cc.self.Name,
-		"self_id", cc.self.Id,
-		"self_address", cc.self.Address,
-	)
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if config.Propagator == nil {
+		config.Propagator = propagation.TraceContext{}
+	}
+
+	if config.Transport == nil {
+		logger := slog.New(config.LogHandler).With("name", config.Name)
+		config.Transport = newGRPCTransport(config.TLS, config.GRPCServerOptions, config.DialOptions, logger)
+	}
+
+	tr := tracing{
+		tracer:     config.TracerProvider.Tracer(tracerName),
+		propagator: config.Propagator,
+	}
+
+	h := &host{
+		bindAddr:             config.BindAddr,
+		advertise:            config.AdvAddr,
+		advAddr:              config.AdvAddr,
+		logHandler:           config.LogHandler,
+		transport:            config.Transport,
+		clients:              newConnectionCache(config.Transport, config.Clock, config.Metrics, 1*time.Hour, config.MaxConnections, config.MaxConnectionFailures),
+		metrics:              config.Metrics,
+		tracing:              tr,
+		rpcTimeout:           config.RPCTimeout,
+		retryPolicy:          config.RetryPolicy,
+		detector:             config.FailureDetector,
+		rng:                  rand.New(config.RandSource),
+		ranges:               make(map[int]Range),
+		rangesChangeCallback: config.OnRangesChange,
+		onIsolation:          config.OnIsolation,
+		done:                 make(chan struct{}),
+		pending:              make(map[uint64]Timer),
+	}
+
+	// the first virtual node keeps the plain name, so a process without
+	// virtual nodes has the same position on the ring as before.
+	for i := range int(config.VirtualNodes) {
+		key := config.Name
+		if i > 0 {
+			key = fmt.Sprintf("%s#%d", config.Name, i)
+		}
+		id := config.IDFunc([]byte(key))
+		if i == 0 && config.ID != nil {
+			id = *config.ID
+		}
+		h.vnodes = append(h.vnodes, newNode(config, h, i, id))
+	}
 
-	cc.clients = newConnectionCache(1 * time.Hour)
+	h.clients.wrap = h.outbound
+
+	cc := h.vnodes[0]
+	h.rpc = &rpcHandler{concord: cc}
+	if config.Admin {
+		h.admin = &adminHandler{host: h}
+	}
+
+	return cc
+}
+
+// creates a single position on the ring, hosted by h.
+func newNode(config Config, h *host, vnode int, id ID) *Concord {
+	cc := &Concord{}
+	cc.self = Server{
+		Name:    config.Name,
+		Id:      id,
+		Address: config.AdvAddr,
+	}
+
+	cc.host = h
+	cc.vnode = vnode
+
+	cc.successorCount = config.SuccessorCount
+	cc.successorAlive = make(map[ID]bool)
+
+	cc.hashFunc = config.IDFunc
+	cc.hashBits = config.HashBits
+
+	cc.maxHops = config.MaxHops
+	cc.lookupMode = config.LookupMode
+
+	cc.rangeChangeCallback = config.OnRangeChange
+
+	cc.logger = cc.newLogger(config.VirtualNodes > 1)
 
 	cc.stabilizeInterval = config.StabilizeInterval
-	cc.stabilizeCtx, cc.stabilizeCancel = context.WithCancel(context.Background())
+	cc.joinBackoff = config.JoinBackoff
+	cc.clock = config.Clock
 
 	cc.initFingerTable()
 
@@ -103,26 +232,18 @@ func initCrypto(certFile string, keyFile string, caFile string) (tls.Certificate
 }
 
 func (c *Concord) initFingerTable() {
-	m := uint64(c.hashBits)
+	m := c.hashBits
 	c.finger = make([]fingerEntry, m)
 	for i := range m {
-		var start uint64
-		if c.hashBits == 64 {
-			start = c.self.Id + (1 << i)
-		} else {
-			start = (c.self.Id + (1 << i)) % (1 << uint64(c.hashBits))
-		}
-
 		c.finger[i] = fingerEntry{
-			Start: start,
+			Start: c.self.Id.add(pow2(i)).mask(c.hashBits),
 			Node:  nil,
 		}
 	}
 }
 
 func (c *Concord) fillFingerTable(n *Server) {
-	m := uint64(c.hashBits)
-	for i := range m {
+	for i := range c.hashBits {
 		c.finger[i].Node = n
 	}
 }
@@ -133,7 +254,9 @@ func (c *Concord) fixFinger(ctx context.Context, idx uint) error {
 		return fmt.Errorf("failed fixing finger %d: %w", idx, err)
 	}
 
+	c.lock.Lock()
 	c.finger[idx].Node = &node
+	c.lock.Unlock()
 	return nil
 }
 
@@ -151,14 +274,14 @@ func (c *Concord) create() error {
 		c.successors[i] = c.self
 	}
 
-	c.predecessor = &c.self
+	c.setPredecessor(&c.self)
 	c.updateRange(Range{c.self.Id, c.self.Id})
 
 	c.fillFingerTable(&c.self)
 
 	c.setup = true
 
-	go c.stabilizeTask(c.stabilizeCtx)
+	c.stabilizeTask()
 	return nil
 }
 
@@ -186,124 +309,487 @@ func head[T any](slice []T) []T {
 	return slice[:1]
 }
 
-// @todo: bootstrap from multiple nodes must be possible.
-func (c *Concord) join(ctx context.Context, bootstrap string) error {
-	c.logger.Info("joining cluster", "bootstrap", bootstrap)
+// joins the ring through any of the seeds. Seeds are tried in a random order
+// each round, backing off between rounds until the context expires.
+func (c *Concord) join(ctx context.Context, seeds []string) error {
+	if len(seeds) == 0 {
+		return fmt.Errorf("no seeds to join through")
+	}
 
-	const JOIN_BACKOFF = time.Second
+	c.logger.Info("joining cluster", "seeds", seeds)
 
-	retryTicker := time.NewTicker(time.Second)
+	failures := make([]error, len(seeds))
 
 	for {
-		select {
-		case <-ctx.Done():
-			retryTicker.Stop()
+		for _, i := range c.host.randPerm(len(seeds)) {
+			if ctx.Err() != nil {
+				break
+			}
+
+			err := c.joinVia(ctx, seeds[i])
+			if err == nil {
+				return nil
+			}
+			// retrying will not free up our ID.
+			if errors.Is(err, ErrIDCollision) {
+				return fmt.Errorf("seed %s: %w", seeds[i], err)
+			}
+
+			c.logger.Error("failed to join through seed", "seed", seeds[i], "error", err)
+			failures[i] = fmt.Errorf("seed %s: %w", seeds[i], err)
+		}
+
+		if c.clock.Sleep(ctx, c.joinBackoff) != nil {
+			if err := errors.Join(failures...); err != nil {
+				return fmt.Errorf("join cancelled: %w", err)
+			}
 			return fmt.Errorf("join cancelled")
-		case <-retryTicker.C:
-			cli, err := c.client(bootstrap)
-			if err != nil {
-				c.logger.Error("failed to connect to bootstrap node", "error", err)
-				continue
+		}
+	}
+}
+
+// attempts to join the ring through a single seed.
+func (c *Concord) joinVia(ctx context.Context, seed string) error {
+	cli, err := c.clientAddr(seed)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	resp, err := cli.FindSuccessor(ctx, findReq{id: c.self.Id})
+	if err != nil {
+		return fmt.Errorf("failed to find successor: %w", err)
+	}
+	successor := resp.server
+	if err := c.checkCollision(successor); err != nil {
+		return err
+	}
+	c.logger.Info("found successor", "successor", successor.Name)
+
+	cli, err = c.client(successor)
+	if err != nil {
+		return fmt.Errorf("failed to connect to successor: %w", err)
+	}
+	r, err := cli.GetRing(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ring from successor: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if r.Predecessor == nil {
+		return fmt.Errorf("successor has no predecessor")
+	}
+
+	if err := c.checkCollision(*r.Predecessor); err != nil {
+		return err
+	}
+	for _, s := range r.Successors {
+		if err := c.checkCollision(s); err != nil {
+			return err
+		}
+	}
+
+	// insert ourselves into the ring;
+	c.successors = append([]Server{successor}, truncate(r.Successors, int(c.successorCount)-1)...)
+	c.setPredecessor(r.Predecessor)
+
+	c.logger.Info("joined cluster", "successor", c.successors[0].Name, "predecessor", c.predecessor.Name)
+
+	c.updateRange(Range{c.predecessor.Id, c.self.Id})
+
+	c.fillFingerTable(&successor)
+
+	c.setup = true
+
+	c.stabilizeTask()
+
+	return nil
+}
+
+// fails if s holds our ID. An entry with our own address is a stale view of
+// ourselves from before a restart, and is replaced by stabilization.
+func (c *Concord) checkCollision(s Server) error {
+	if s.Id != c.self.Id || s.Address == c.self.Address {
+		return nil
+	}
+	return fmt.Errorf("%w: %s already held by %s at %s", ErrIDCollision, c.self.Id, s.Name, s.Address)
+}
+
+// leaves the ring; the successor takes over our range and the predecessor
+// splices us out of its successor list.
+func (c *Concord) leave(ctx context.Context) error {
+	c.lock.Lock()
+	if !c.setup {
+		c.lock.Unlock()
+		return fmt.Errorf("not part of a cluster")
+	}
+
+	// stop stabilizing first, so we do not notify ourselves back into the ring.
+	c.setup = false
+	c.stabilizeTicker.Stop()
+	c.stabilizeCancel()
+
+	r := ring{
+		Successors:  make([]Server, len(c.successors)),
+		Predecessor: c.predecessor,
+	}
+	copy(r.Successors, c.successors)
+	c.lock.Unlock()
+
+	succ := r.Successors[0]
+	if succ == c.self {
+		c.logger.Info("left cluster as the last node")
+		return nil
+	}
+
+	c.logger.Info("leaving cluster", "successor", succ.Name)
+
+	neighbours := []Server{succ}
+	if pred := r.Predecessor; pred != nil && *pred != succ && *pred != c.self {
+		neighbours = append(neighbours, *pred)
+	}
+
+	for _, n := range neighbours {
+		cli, err := c.client(n)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", n.Name, err)
+		}
+		if err := cli.Leave(ctx, c.self, r); err != nil {
+			return fmt.Errorf("failed to leave through %s: %w", n.Name, err)
+		}
+	}
+
+	c.logger.Info("left cluster")
+	return nil
+}
+
+func (c *Concord) stopStabilizing() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.setup {
+		c.setup = false
+		c.stabilizeTicker.Stop()
+		c.stabilizeCancel()
+	}
+}
+
+func (c *Concord) ready() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.setup
+}
+
+func (c *Concord) findSuccessor(ctx context.Context, id ID) (Server, error) {
+	resp, err := c.route(ctx, findReq{id: id})
+	if err != nil {
+		return Server{}, err
+	}
+	return resp.server, nil
+}
+
+// routes a lookup originating at this node, in the configured lookup mode.
+func (c *Concord) route(ctx context.Context, req findReq) (findResp, error) {
+	ctx, span := c.host.tracing.start(ctx, "concord.Lookup", trace.SpanKindInternal,
+		attribute.String("concord.id", req.id.String()),
+		attribute.String("concord.node", c.self.Name),
+	)
+
+	var resp findResp
+	var err error
+	start := c.clock.Now()
+	if c.lookupMode == LookupIterative {
+		resp, err = c.lookupIterative(ctx, req)
+	} else {
+		resp, err = c.lookup(ctx, req)
+	}
+
+	c.host.stats.lookups.Add(1)
+	if err != nil {
+		c.host.stats.lookupFailures.Add(1)
+	}
+	c.host.metrics.ObserveLookup(c.clock.Now().Sub(start), int(resp.hopCount), err)
+
+	if err == nil {
+		span.SetAttributes(
+			attribute.String("concord.owner", resp.server.Name),
+			attribute.Int("concord.hops", int(resp.hopCount)),
+		)
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+// resolves a lookup by asking each hop for the next one. If a hop fails, the
+// successors of the previous hop are tried instead.
+func (c *Concord) lookupIterative(ctx context.Context, req findReq) (findResp, error) {
+	var resp findResp
+	var visited []ID
+	var lastErr error
+
+	candidates := []Server{c.self}
+	for {
+		if uint(len(visited)) > c.maxHops {
+			return findResp{}, ErrRoutingLoop
+		}
+
+		var step lookupStep
+		var hop Server
+		var latency time.Duration
+		forwarded := false
+		found := false
+		failed := false
+		for i, cand := range candidates {
+			if err := ctx.Err(); err != nil {
+				return findResp{}, contextError(err)
 			}
-			successor, err := cli.FindSuccessor(ctx, c.self.Id)
-			if err != nil {
-				c.logger.Error("failed find successor, retrying", "error", err)
+			if slices.Contains(visited, cand.Id) {
 				continue
 			}
-			c.logger.Info("found successor", "successor", successor.Name)
-
-			// @note: micro optimization.
-			var r ring
-			if successor.Address != bootstrap {
-				cli, err = c.client(successor.Address)
-				if err != nil {
-					c.logger.Error("failed to connect to successor, retrying", "error", err)
-					continue
-				}
+
+			if failed {
+				c.host.metrics.ObserveFallback()
 			}
-			r, err = cli.GetRing(ctx)
+
+			cli, err := c.client(cand)
 			if err != nil {
-				c.logger.Error("failed to get ring from successor, retrying", "error", err)
+				lastErr, failed = err, true
 				continue
 			}
 
-			c.lock.Lock()
-			if r.Predecessor == nil {
-				c.logger.Error("successor has no predecessor, retrying")
-				c.lock.Unlock()
+			forwarded = true
+			c.host.stats.forwards.Add(1)
+			c.host.metrics.ObserveForward(cand.Address)
+			start := c.clock.Now()
+			step, err = cli.ClosestPreceding(ctx, req)
+			if err != nil {
+				c.logger.Info("hop failed, trying next", "hop", cand.Name, "id", req.id, "error", err)
+				lastErr, failed = err, true
 				continue
 			}
 
-			// insert ourselves into the ring;
-			c.successors = append([]Server{successor}, truncate(r.Successors, int(c.successorCount)-1)...)
-			c.predecessor = r.Predecessor
+			// the suggested finger failed; mark the previous hop as a fallback.
+			if i > 0 && len(resp.hops) > 0 {
+				resp.hops[len(resp.hops)-1].Finger = HopSuccessor
+			}
+			hop, latency, found = cand, c.clock.Now().Sub(start), true
+			break
+		}
 
-			c.logger.Info("joined cluster", "successor", c.successors[0].Name, "predecessor", c.predecessor.Name)
+		if !found {
+			if err := ctx.Err(); err != nil {
+				return findResp{}, contextError(err)
+			}
+			if !forwarded {
+				if lastErr == nil {
+					return findResp{}, ErrRoutingLoop
+				}
+				return findResp{}, fmt.Errorf("%w: %w", ErrNoRoute, lastErr)
+			}
+			return findResp{}, fmt.Errorf("%w: %w", ErrAllContendersFailed, lastErr)
+		}
 
-			c.updateRange(Range{c.predecessor.Id, c.self.Id})
+		visited = append(visited, hop.Id)
+		if req.trace {
+			resp.hops = append(resp.hops, Hop{Node: hop, Finger: step.finger, Latency: latency})
+		}
 
-			c.fillFingerTable(&successor)
+		if step.resolved {
+			resp.server = step.server
+			resp.hopCount = uint(len(visited))
+			return resp, nil
+		}
+		candidates = append([]Server{step.server}, step.successors...)
+	}
+}
 
-			c.setup = true
+// answers a single step of an iterative lookup: either the successor of the
+// id, or the closest preceding node to continue from.
+func (c *Concord) closestPreceding(req findReq) (lookupStep, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if !c.setup {
+		return lookupStep{}, ErrNotReady
+	}
 
-			go c.stabilizeTask(c.stabilizeCtx)
+	if between(c.self.Id, req.id, c.successors[0].Id) {
+		return lookupStep{resolved: true, server: c.successors[0], finger: HopResolved}, nil
+	}
 
-			c.lock.Unlock()
-			return nil
-		}
+	n, finger := c.closestPrecedingNode(req.id)
+	if n == c.self {
+		return lookupStep{resolved: true, server: c.self, finger: HopResolved}, nil
 	}
+
+	successors := make([]Server, len(c.successors))
+	copy(successors, c.successors)
+
+	return lookupStep{server: n, finger: finger, successors: successors}, nil
 }
 
-func (c *Concord) findSuccessor(ctx context.Context, id uint64) (Server, error) {
+// resolves a findSuccessor request, recording the hops taken if it is traced.
+func (c *Concord) lookup(ctx context.Context, req findReq) (findResp, error) {
+	id := req.id
+
 	c.lock.RLock()
 	c.logger.Info("finding successor", "id", id, "successors", c.successors)
 	if !c.setup {
 		defer c.lock.RUnlock()
-		return Server{}, fmt.Errorf("not ready")
+		return findResp{}, ErrNotReady
+	}
+
+	if slices.Contains(req.visited, c.self.Id) || req.hops > c.maxHops {
+		defer c.lock.RUnlock()
+		c.logger.Warn("rejecting findSuccessor", "id", id, "hops", req.hops, "error", ErrRoutingLoop)
+		return findResp{}, ErrRoutingLoop
+	}
+
+	resolved := func(s Server) findResp {
+		resp := findResp{server: s, hopCount: 1}
+		if req.trace {
+			resp.hops = []Hop{{Node: c.self, Finger: HopResolved}}
+		}
+		return resp
 	}
 
 	if between(c.self.Id, id, c.successors[0].Id) {
 		defer c.lock.RUnlock()
-		return c.successors[0], nil
+		return resolved(c.successors[0]), nil
 	}
 
-	n := c.closestPrecedingNode(id)
+	n, finger := c.closestPrecedingNode(id)
 	if n == c.self {
 		defer c.lock.RUnlock()
-		return c.self, nil
+		return resolved(c.self), nil
 	}
 
 	// forward request to closest preceeding node first; if fails (due to churn), try successors.
 	contenders := append([]Server{n}, c.successors...)
 	var lastErr error
+	forwarded := false
+
+	next := req
+	next.hops++
+	next.visited = append(slices.Clip(req.visited), c.self.Id)
 	c.lock.RUnlock()
 
-	for _, contender := range contenders {
-		cli, err := c.client(contender.Address)
+	for i, contender := range contenders {
+		if err := ctx.Err(); err != nil {
+			return findResp{}, contextError(err)
+		}
+		if lastErr != nil {
+			c.host.metrics.ObserveFallback()
+		}
+
+		cli, err := c.client(contender)
 		if err != nil {
+			lastErr = err
 			continue
 		}
 
 		c.logger.Info("forwarding findSuccessor", "to", contender.Name, "id", id)
-		succ, err := cli.FindSuccessor(ctx, id)
+		forwarded = true
+		c.host.stats.forwards.Add(1)
+		c.host.metrics.ObserveForward(contender.Address)
+		start := c.clock.Now()
+		resp, err := cli.FindSuccessor(ctx, next)
 		if err == nil {
-			return succ, nil
+			resp.hopCount++
+			if req.trace {
+				hop := Hop{Node: c.self, Finger: finger, Latency: c.clock.Now().Sub(start)}
+				if i > 0 {
+					hop.Finger = HopSuccessor
+				}
+				resp.hops = append([]Hop{hop}, resp.hops...)
+			}
+			return resp, nil
 		}
 		lastErr = err
+
+		// the contender already tried every route onwards; trying ours as well
+		// would search the same routes again at every hop.
+		if errors.Is(err, ErrAllContendersFailed) {
+			break
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return findResp{}, contextError(err)
 	}
-	return Server{}, lastErr
+	if !forwarded {
+		return findResp{}, fmt.Errorf("%w: %w", ErrNoRoute, lastErr)
+	}
+	return findResp{}, fmt.Errorf("%w: %w", ErrAllContendersFailed, lastErr)
 }
 
-func (c *Concord) closestPrecedingNode(id uint64) Server {
+// finds the owner of id followed by its successors, up to n on distinct servers.
+func (c *Concord) findReplicas(ctx context.Context, id ID, n int) ([]Server, error) {
+	if n <= 0 {
+		return []Server{}, nil
+	}
+
+	owner, err := c.findSuccessor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := []Server{owner}
+	if n == 1 {
+		return replicas, nil
+	}
+
+	cli, err := c.client(owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to owner: %w", err)
+	}
+	r, err := cli.GetRing(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ring from owner: %w", err)
+	}
+
+	// small rings wrap around, and virtual nodes share a server; skip servers
+	// already in the set.
+	for _, s := range r.Successors {
+		if len(replicas) == n {
+			break
+		}
+		if !slices.ContainsFunc(replicas, func(o Server) bool { return o.Address == s.Address }) {
+			replicas = append(replicas, s)
+		}
+	}
+
+	return replicas, nil
+}
+
+// returns the closest preceding node of id, and the index of its finger; or
+// HopSuccessor if it is from the successor list.
+func (c *Concord) closestPrecedingNode(id ID) (Server, int) {
+	best, finger := c.self, HopResolved
 	for i := int(c.hashBits - 1); i >= 0; i-- {
 		if c.finger[i].Node != nil && between(c.self.Id, c.finger[i].Node.Id, id) {
-			return *c.finger[i].Node
+			best, finger = *c.finger[i].Node, i
+			break
+		}
+	}
+
+	// fingers are fixed one per round, and may all point past id after joins;
+	// the successor list is kept up to date by every round.
+	for _, s := range c.successors {
+		if between(best.Id, s.Id, id) {
+			best, finger = s, HopSuccessor
 		}
 	}
-	return c.self
+	return best, finger
 }
 
 func (c *Concord) rectify(ctx context.Context, srv Server) {
+	ctx, span := c.host.tracing.start(ctx, "concord.Rectify", trace.SpanKindInternal,
+		attribute.String("concord.node", c.self.Name),
+		attribute.String("concord.candidate", srv.Name),
+	)
+	defer span.End()
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	if !c.setup {
@@ -312,32 +798,79 @@ func (c *Concord) rectify(ctx context.Context, srv Server) {
 
 	// c.logger.Debug("rectifying", "srv", srv)
 	if c.predecessor == nil || between(c.predecessor.Id, srv.Id, c.self.Id) {
-		c.predecessor = &srv
+		prev := c.predecessor
+		c.setPredecessor(&srv)
+		c.secondPredecessor = prev
 		c.updateRange(Range{srv.Id, c.self.Id})
 	} else {
-		cli, _ := c.client(c.predecessor.Address)
+		pred := *c.predecessor
+		cli, _ := c.client(pred)
 
 		// query liveness from predecessor
 		c.lock.Unlock()
 		_, err := cli.GetRing(ctx)
 		c.lock.Lock()
 
-		if err != nil {
-			c.predecessor = &srv
+		if err == nil {
+			c.peerAnswered(pred)
+		} else if c.peerFailed(pred) {
+			c.setPredecessor(&srv)
 			c.updateRange(Range{c.predecessor.Id, c.self.Id})
 		}
 	}
 }
 
-func (c *Concord) stabilizeFromSuccessor(ctx context.Context) {
+// splices a leaving neighbour out of the ring, using its view of the ring.
+func (c *Concord) spliceOut(leaving Server, r ring) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.setup {
+		return
+	}
+
+	if c.successors[0].Id == leaving.Id {
+		var succs []Server
+		for _, s := range r.Successors {
+			if s.Id != leaving.Id {
+				succs = append(succs, s)
+			}
+		}
+		if len(succs) == 0 {
+			succs = []Server{c.self}
+		}
+
+		c.successors = truncate(succs, int(c.successorCount))
+		c.logger.Info("successor left", "leaving", leaving.Name, "successor", c.successors[0].Name)
+	}
+
+	if c.predecessor != nil && c.predecessor.Id == leaving.Id {
+		pred := r.Predecessor
+		if pred == nil || pred.Id == leaving.Id {
+			pred = &c.self
+		}
+
+		c.setPredecessor(pred)
+		c.updateRange(Range{pred.Id, c.self.Id})
+		c.logger.Info("predecessor left", "leaving", leaving.Name, "predecessor", pred.Name)
+	}
+}
+
+// refreshes the successor list from the first reachable successor. Returns the
+// errors of the successors found unreachable.
+func (c *Concord) stabilizeFromSuccessor(ctx context.Context) error {
+	var errs []error
 	for {
 		c.lock.RLock()
-		cli, _ := c.client(c.successors[0].Address)
+		succ := c.successors[0]
+		cli, _ := c.client(succ)
 		c.lock.RUnlock()
 		r, err := cli.GetRing(ctx)
 
 		c.lock.Lock()
+		c.successorAlive[succ.Id] = err == nil
 		if err == nil {
+			c.peerAnswered(succ)
+			c.host.sawRing(r, c.clock.Now())
 			if uint(len(c.successors)) < c.successorCount {
 				c.successors = append(head(c.successors), r.Successors...)
 			} else {
@@ -353,29 +886,104 @@ func (c *Concord) stabilizeFromSuccessor(ctx context.Context) {
 				c.lock.Unlock()
 			}
 
-			go c.notifySuccessor(ctx)
+			c.background(func() { c.notifySuccessor(ctx) })
 
-			break
+			return errors.Join(errs...)
 		} else {
+			c.host.stats.stabilizeFailures.Add(1)
+			errs = append(errs, fmt.Errorf("successor %s unreachable: %w", succ.Name, err))
+
+			// a suspected successor is probed again next round, before it is
+			// given up on.
+			if !c.peerFailed(succ) {
+				c.lock.Unlock()
+				return errors.Join(errs...)
+			}
+
 			if len(c.successors) == 1 {
 				c.logger.Info("failed to reach all successors; complete isolation")
 				c.successors = []Server{c.self}
-				c.predecessor = &c.self
+				c.setPredecessor(&c.self)
 				c.lock.Unlock()
-				go c.notifySuccessor(ctx)
-				return
+				c.background(func() { c.notifySuccessor(ctx) })
+				return errors.Join(errs...)
 			} else {
 				c.successors = tail(c.successors)
 			}
 			c.lock.Unlock()
 		}
 
-		go c.notifySuccessor(ctx)
+		c.background(func() { c.notifySuccessor(ctx) })
 	}
 }
 
+// probes the rest of the successor list concurrently, as the first successor
+// was contacted already, so that dead entries do not go unnoticed until they
+// become the first. Entries declared dead are pruned; the list is refilled
+// from the first successor on the next round.
+func (c *Concord) probeSuccessors(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	seen := map[ID]bool{c.self.Id: true, c.successors[0].Id: true}
+	var probes []Server
+	for _, s := range tail(c.successors) {
+		if !seen[s.Id] {
+			seen[s.Id] = true
+			probes = append(probes, s)
+		}
+	}
+
+	// forget about nodes that left the list.
+	for id := range c.successorAlive {
+		if !seen[id] {
+			delete(c.successorAlive, id)
+		}
+	}
+
+	for _, s := range probes {
+		c.background(func() { c.probeSuccessor(ctx, s) })
+	}
+}
+
+func (c *Concord) probeSuccessor(ctx context.Context, s Server) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	cli, _ := c.client(s)
+	_, err := cli.GetRing(ctx)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.setup {
+		return
+	}
+
+	c.successorAlive[s.Id] = err == nil
+	if err == nil {
+		c.peerAnswered(s)
+		return
+	}
+
+	c.host.stats.stabilizeFailures.Add(1)
+	c.logger.Warn("successor unreachable", "successor", s.Name, "error", err)
+	if !c.peerFailed(s) {
+		return
+	}
+
+	// the first successor is left to stabilizeFromSuccessor.
+	pruned := []Server{c.successors[0]}
+	for _, t := range tail(c.successors) {
+		if t.Id != s.Id {
+			pruned = append(pruned, t)
+		}
+	}
+	c.successors = pruned
+}
+
 func (c *Concord) stabilizeFromPredecessor(ctx context.Context, newSucc Server) {
-	pcli, err := c.client(newSucc.Address)
+	pcli, err := c.client(newSucc)
 	if err != nil {
 		return
 	}
@@ -388,9 +996,65 @@ func (c *Concord) stabilizeFromPredecessor(ctx context.Context, newSucc Server)
 	}
 }
 
+// probes the predecessor, as check_predecessor in the Chord paper. A dead
+// predecessor is replaced by its own predecessor, so that our range covers the
+// keys it held without waiting for a node to notify us; if that one is not
+// known, the predecessor is cleared until one does, and the range last
+// reported is kept, as where it starts is not known. Predecessors are only
+// hints to Zave's invariants, so a wrong replacement is corrected by rectify.
+func (c *Concord) checkPredecessor(ctx context.Context) error {
+	c.lock.RLock()
+	if !c.setup || c.predecessor == nil || c.predecessor.Id == c.self.Id {
+		c.lock.RUnlock()
+		return nil
+	}
+	pred := *c.predecessor
+	cli, _ := c.client(pred)
+	c.lock.RUnlock()
+
+	r, err := cli.GetRing(ctx)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the predecessor changed while we waited.
+	if !c.setup || c.predecessor == nil || c.predecessor.Id != pred.Id {
+		return nil
+	}
+
+	if err == nil {
+		c.peerAnswered(pred)
+		if r.Predecessor != nil && r.Predecessor.Id != pred.Id {
+			second := *r.Predecessor
+			c.secondPredecessor = &second
+		}
+		return nil
+	}
+
+	c.host.stats.stabilizeFailures.Add(1)
+	err = fmt.Errorf("predecessor %s unreachable: %w", pred.Name, err)
+	if !c.peerFailed(pred) {
+		return err
+	}
+
+	second := c.secondPredecessor
+	c.secondPredecessor = nil
+	if second == nil || second.Id == pred.Id {
+		c.logger.Info("predecessor died", "predecessor", pred.Name)
+		c.host.metrics.ObservePredecessorChange()
+		c.predecessor = nil
+		return err
+	}
+
+	c.logger.Info("predecessor died", "predecessor", pred.Name, "replacement", second.Name)
+	c.setPredecessor(second)
+	c.updateRange(Range{second.Id, c.self.Id})
+	return err
+}
+
 func (c *Concord) notifySuccessor(ctx context.Context) error {
 	c.lock.RLock()
-	cli, err := c.client(c.successors[0].Address)
+	cli, err := c.client(c.successors[0])
 	c.lock.RUnlock()
 
 	if err != nil {
@@ -403,54 +1067,260 @@ func (c *Concord) notifySuccessor(ctx context.Context) error {
 	return nil
 }
 
-func (c *Concord) stabilizeTask(ctx context.Context) {
-	ticker := time.NewTicker(c.stabilizeInterval)
+// stabilizes every stabilizeInterval, until stopped by stopStabilizing or
+// leave. Must be called with the lock held.
+func (c *Concord) stabilizeTask() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stabilizeCtx, c.stabilizeCancel = ctx, cancel
+
+	c.stabilizeTicker = c.clock.NewTicker(c.stabilizeInterval, func() {
+		// a round forced through the admin service may be running; this one is
+		// not needed then.
+		c.stabilizeRound(ctx, false)
+	})
+}
+
+// runs a round of stabilization as a background task, which Stop waits for.
+// If another round is running, waits for it to finish first, or skips this
+// one. Returns false if the round did not run.
+func (c *Concord) stabilizeRound(ctx context.Context, wait bool) bool {
+	h := c.host
+	if ctx.Err() != nil || !h.enter() {
+		return false
+	}
+	defer h.tasks.Done()
 
-	for {
-		select {
-		case <-ctx.Done():
-			ticker.Stop()
-			return
-		case <-ticker.C:
-			c.stabilizeFromSuccessor(ctx)
-
-			fingerToFix := rand.UintN(c.hashBits)
-			if err := c.fixFinger(ctx, fingerToFix); err != nil {
-				c.logger.Warn(err.Error())
-			}
+	if wait {
+		c.stabilizing.Lock()
+	} else if !c.stabilizing.TryLock() {
+		return false
+	}
+	defer c.stabilizing.Unlock()
+
+	c.stabilize(ctx)
+	return true
+}
+
+// runs a single round of stabilization: refreshes the successor list, notifies
+// the successor and fixes a random finger.
+func (c *Concord) stabilize(ctx context.Context) {
+	ctx, span := c.host.tracing.start(ctx, "concord.Stabilize", trace.SpanKindInternal,
+		attribute.String("concord.node", c.self.Name),
+	)
+
+	start := c.clock.Now()
+	err := c.stabilizeFromSuccessor(ctx)
+	err = errors.Join(err, c.checkPredecessor(ctx))
+	c.probeSuccessors(ctx)
+
+	fingerToFix := c.host.randUintN(c.hashBits)
+	if ferr := c.fixFinger(ctx, fingerToFix); ferr != nil {
+		c.host.stats.stabilizeFailures.Add(1)
+		c.logger.Warn(ferr.Error())
+		err = errors.Join(err, ferr)
+	}
+
+	c.lock.RLock()
+	if c.predecessor != nil {
+		c.logger.Debug("stabilized", "successor", c.successors[0].Name, "predecessor", c.predecessor.Name)
+	}
+	successors := len(c.successors)
+	c.lock.RUnlock()
+
+	c.host.metrics.SetSuccessors(c.vnode, successors)
+	c.host.metrics.ObserveStabilize(c.clock.Now().Sub(start), err)
+	endSpan(span, err)
+
+	c.host.checkIsolation()
+}
+
+// runs f in the background. It is scheduled on the clock, so that simulations
+// decide when it runs, and Stop waits for it.
+func (c *Concord) background(f func()) {
+	c.host.spawn(c.clock, 0, f)
+}
 
-			c.lock.RLock()
-			c.logger.Debug("stabilized", "successor", c.successors[0].Name, "predecessor", c.predecessor.Name)
-			c.lock.RUnlock()
+// records that s answered a probe.
+func (c *Concord) peerAnswered(s Server) {
+	c.host.probed(s.Address, true, c.clock.Now(), c.stabilizeInterval)
+}
+
+// records that a probe of s failed, and reports whether s is now considered
+// dead.
+func (c *Concord) peerFailed(s Server) bool {
+	status, changed := c.host.probed(s.Address, false, c.clock.Now(), c.stabilizeInterval)
+	if changed {
+		switch status {
+		case PeerDead:
+			c.host.stats.deaths.Add(1)
+			c.logger.Info("peer declared dead", "peer", s.Name, "address", s.Address)
+		case PeerSuspected:
+			c.host.stats.suspicions.Add(1)
+			c.logger.Info("peer suspected", "peer", s.Name, "address", s.Address)
 		}
 	}
+	return status == PeerDead
+}
+
+// sets the predecessor. If it changed, reports that to Metrics and forgets the
+// second predecessor. Must be called with the lock held.
+func (c *Concord) setPredecessor(p *Server) {
+	if c.predecessor == nil || c.predecessor.Id != p.Id {
+		c.host.metrics.ObservePredecessorChange()
+		c.secondPredecessor = nil
+	}
+	c.predecessor = p
 }
 
 func (c *Concord) updateRange(r Range) {
+	c.host.metrics.ObserveRangeChange()
 	c.interval = r
 	if c.rangeChangeCallback != nil {
 		c.rangeChangeCallback(r)
 	}
+
+	h := c.host
+	h.rangesLock.Lock()
+	defer h.rangesLock.Unlock()
+
+	h.ranges[c.vnode] = r
+	if h.rangesChangeCallback != nil {
+		h.rangesChangeCallback(h.rangesSnapshot())
+	}
+}
+
+// returns the ranges of all virtual nodes, ordered by virtual node. Must be
+// called with rangesLock held.
+func (h *host) rangesSnapshot() []Range {
+	ranges := make([]Range, 0, len(h.ranges))
+	for i := range h.vnodes {
+		if r, ok := h.ranges[i]; ok {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// wraps a connection to the node at addr for the requests of the host: each
+// attempt is traced and bounded by the RPC timeout, and failed ones retried.
+func (h *host) outbound(addr string, conn grpc.ClientConnInterface) grpc.ClientConnInterface {
+	traced := &tracedConn{ClientConnInterface: conn, tracing: h.tracing, addr: addr}
+	return &retryConn{
+		ClientConnInterface: traced,
+		timeout:             h.rpcTimeout,
+		policy:              h.retryPolicy,
+		clock:               h.vnodes[0].clock,
+		host:                h,
+	}
+}
+
+func (h *host) randPerm(n int) []int {
+	h.rngLock.Lock()
+	defer h.rngLock.Unlock()
+	return h.rng.Perm(n)
+}
+
+func (h *host) randUintN(n uint) uint {
+	h.rngLock.Lock()
+	defer h.rngLock.Unlock()
+	return h.rng.UintN(n)
+}
+
+func (h *host) randFloat64() float64 {
+	h.rngLock.Lock()
+	defer h.rngLock.Unlock()
+	return h.rng.Float64()
 }
 
 // returns true if a < b < c where a ring is respected.
-func between(a, b, c uint64) bool {
-	if a < c {
-		return a < b && b < c
+func between(a, b, c ID) bool {
+	if a.Cmp(c) < 0 {
+		return a.Cmp(b) < 0 && b.Cmp(c) < 0
 	} else {
-		return a < b || b < c
+		return a.Cmp(b) < 0 || b.Cmp(c) < 0
 	}
 }
 
-func (c *Concord) client(addr string) (rpcClient, error) {
-	if addr == c.advAddr {
-		return newClientDispatch(c.rpc), nil
+// returns a client for the given server; requests are routed to its virtual node.
+func (c *Concord) client(s Server) (rpcClient, error) {
+	cli, err := c.clientAddr(s.Address)
+	if err != nil {
+		return nil, err
 	}
+	return cli.withTarget(s.Id), nil
+}
+
+// returns a logger annotated with the position of the node.
+func (c *Concord) newLogger(virtual bool) *slog.Logger {
+	logger := slog.New(c.host.logHandler).With(
+		"name", c.self.Name,
+		"self_id", c.self.Id,
+		"self_address", c.self.Address,
+	)
+	if virtual {
+		logger = logger.With("vnode", c.vnode)
+	}
+	return logger
+}
+
+// sets the address advertised by every virtual node.
+func (h *host) setAddress(addr string) {
+	h.advAddr = addr
+	for _, v := range h.vnodes {
+		v.lock.Lock()
+		v.self.Address = addr
+		v.logger = v.newLogger(len(h.vnodes) > 1)
+		v.lock.Unlock()
+	}
+}
+
+// takes every virtual node out of the ring, after a create, join or leave
+// failed part way.
+func (h *host) resetVnodes() {
+	for _, v := range h.vnodes {
+		v.stopStabilizing()
+	}
+}
+
+// returns the address to advertise for a node listening at bound. The
+// configured address is used as is, unless it holds a {host} or {port}
+// placeholder, which is replaced by that of bound. Without one, bound itself
+// is advertised; an unspecified host, as in ":0", by the host name.
+func advertisedAddr(adv, bound string) (string, error) {
+	if adv != "" && !strings.Contains(adv, "{host}") && !strings.Contains(adv, "{port}") {
+		return adv, nil
+	}
+
+	host, port, err := net.SplitHostPort(bound)
+	if err != nil {
+		// not a network address, as on a memory network.
+		if adv == "" {
+			return bound, nil
+		}
+		return "", fmt.Errorf("cannot fill in %q from listen address %s: %w", adv, bound, err)
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		if host, err = os.Hostname(); err != nil {
+			return "", fmt.Errorf("cannot fill in %q: %w", adv, err)
+		}
+	}
+
+	if adv == "" {
+		return net.JoinHostPort(host, port), nil
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return strings.NewReplacer("{host}", host, "{port}", port).Replace(adv), nil
+}
 
-	tlsConfigClone := c.clientTLS.Clone()
-	if tlsConfigClone != nil {
-		tlsConfigClone.ServerName = addr
+// returns a client for whichever node listens on addr.
+func (c *Concord) clientAddr(addr string) (rpcClient, error) {
+	h := c.host
+	if addr == h.advAddr {
+		return newClientDispatch(h.rpc), nil
 	}
 
-	return c.clients.get(addr, tlsConfigClone)
+	return h.clients.get(addr)
 }
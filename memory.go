@@ -0,0 +1,251 @@
+package concord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// An in-process network of nodes, connected through the transports it hands
+// out. Requests are delivered by calling the handlers of the target node
+// directly, so many nodes can run in one process without TCP ports.
+//
+// Addresses are arbitrary strings. Requests to an address nobody listens on
+// fail with codes.Unavailable, as for a node that is down.
+type MemoryNetwork struct {
+	mu      sync.RWMutex
+	servers map[string]*memoryServer
+	hook    DeliveryHook
+}
+
+// Called with each request sent on a MemoryNetwork, before it is delivered to
+// the node listening at to. The request is dropped if it returns an error; the
+// sender sees that error instead of a response. It may block to delay the
+// request.
+type DeliveryHook func(from, to, method string) error
+
+// Creates an empty in-memory network.
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		servers: make(map[string]*memoryServer),
+	}
+}
+
+// Returns a new transport on the network, for use by a single node.
+func (n *MemoryNetwork) Transport() Transport {
+	return &memoryTransport{network: n}
+}
+
+// Sets the hook requests are passed through before delivery; nil delivers all
+// of them at once.
+func (n *MemoryNetwork) SetDeliveryHook(hook DeliveryHook) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.hook = hook
+}
+
+func (n *MemoryNetwork) deliveryHook() DeliveryHook {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.hook
+}
+
+func (n *MemoryNetwork) server(addr string) (*memoryServer, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	srv, ok := n.servers[addr]
+	return srv, ok
+}
+
+type memoryTransport struct {
+	network *MemoryNetwork
+
+	mu   sync.Mutex
+	addr string
+}
+
+func (t *memoryTransport) Listen(addr string, register func(grpc.ServiceRegistrar)) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.addr != "" {
+		return "", fmt.Errorf("transport already listening")
+	}
+
+	srv := &memoryServer{services: make(map[string]memoryService)}
+	register(srv)
+
+	n := t.network
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.servers[addr]; ok {
+		return "", fmt.Errorf("address %s already in use", addr)
+	}
+	n.servers[addr] = srv
+	t.addr = addr
+
+	return addr, nil
+}
+
+func (t *memoryTransport) Dial(addr string) (ClientConn, error) {
+	return &memoryConn{network: t.network, from: t, addr: addr}, nil
+}
+
+// returns the address listened on; empty if not listening.
+func (t *memoryTransport) address() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.addr
+}
+
+func (t *memoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.addr == "" {
+		return nil
+	}
+
+	n := t.network
+	n.mu.Lock()
+	delete(n.servers, t.addr)
+	n.mu.Unlock()
+
+	t.addr = ""
+	return nil
+}
+
+type memoryService struct {
+	desc *grpc.ServiceDesc
+	impl any
+}
+
+// the services registered by one node.
+type memoryServer struct {
+	services map[string]memoryService
+}
+
+func (s *memoryServer) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	s.services[desc.ServiceName] = memoryService{desc: desc, impl: impl}
+}
+
+// returns the handler of a full method name, as in "/service/method".
+func (s *memoryServer) method(name string) (grpc.MethodDesc, any, bool) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(name, "/"), "/")
+	if !ok {
+		return grpc.MethodDesc{}, nil, false
+	}
+
+	svc, ok := s.services[service]
+	if !ok {
+		return grpc.MethodDesc{}, nil, false
+	}
+	for _, md := range svc.desc.Methods {
+		if md.MethodName == method {
+			return md, svc.impl, true
+		}
+	}
+	return grpc.MethodDesc{}, nil, false
+}
+
+// a connection to an address on a memory network. The target is resolved on
+// every request, so a node that stops listening becomes unavailable.
+type memoryConn struct {
+	network *MemoryNetwork
+	from    *memoryTransport
+	addr    string
+	closed  atomic.Bool
+}
+
+func (c *memoryConn) Invoke(ctx context.Context, method string, args any, reply any, _ ...grpc.CallOption) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if c.closed.Load() {
+		return status.Error(codes.Canceled, "connection closed")
+	}
+
+	if hook := c.network.deliveryHook(); hook != nil {
+		if err := hook(c.from.address(), c.addr, method); err != nil {
+			return status.Convert(err).Err()
+		}
+	}
+
+	srv, ok := c.network.server(c.addr)
+	if !ok {
+		return status.Errorf(codes.Unavailable, "no node listening at %s", c.addr)
+	}
+	desc, impl, ok := srv.method(method)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+
+	// messages are copied, so neither side shares memory with the other.
+	req, err := proto.Marshal(args.(proto.Message))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal request: %v", err)
+	}
+	dec := func(v any) error {
+		return proto.Unmarshal(req, v.(proto.Message))
+	}
+
+	sctx, cancel := serverContext(ctx)
+	defer cancel()
+
+	resp, err := desc.Handler(impl, sctx, dec, nil)
+	if err != nil {
+		return status.Convert(err).Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
+	out, err := proto.Marshal(resp.(proto.Message))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal response: %v", err)
+	}
+	return proto.Unmarshal(out, reply.(proto.Message))
+}
+
+func (c *memoryConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func (c *memoryConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "streams are not supported by the memory transport")
+}
+
+// returns the context a handler sees for a request sent with ctx: carrying its
+// outgoing metadata as incoming, and its deadline and cancellation, but none of
+// its values.
+func serverContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	sctx := metadata.NewIncomingContext(context.Background(), md.Copy())
+
+	var cancel context.CancelFunc
+	if deadline, ok := ctx.Deadline(); ok {
+		sctx, cancel = context.WithDeadline(sctx, deadline)
+	} else {
+		sctx, cancel = context.WithCancel(sctx)
+	}
+
+	stop := context.AfterFunc(ctx, cancel)
+	return sctx, func() {
+		stop()
+		cancel()
+	}
+}
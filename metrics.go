@@ -0,0 +1,53 @@
+package concord
+
+import "time"
+
+// Receives events of a node, for monitoring. One Metrics is shared by all
+// virtual nodes of a process. Methods are called from the goroutines of the
+// node as events happen, so they must be safe for concurrent use and return
+// quickly.
+type Metrics interface {
+	// A lookup originating at this node finished. hops is the number of nodes
+	// that handled it, including this one; zero if it failed.
+	ObserveLookup(latency time.Duration, hops int, err error)
+	// A lookup was forwarded to the node at peer; recursively, or by asking it
+	// for the next hop.
+	ObserveForward(peer string)
+	// A lookup could not be forwarded to a contender, and falls back to the next
+	// one.
+	ObserveFallback()
+	// A round of stabilization of a virtual node finished. err is non-nil if a
+	// successor was unreachable, or the finger could not be fixed.
+	ObserveStabilize(duration time.Duration, err error)
+	// The successor list of a virtual node holds n servers.
+	SetSuccessors(vnode int, n int)
+	// The predecessor of a virtual node changed.
+	ObservePredecessorChange()
+	// The range of a virtual node changed; OnRangeChange is called with it.
+	ObserveRangeChange()
+	// The connection cache holds n connections.
+	SetConnections(n int)
+	// The number of cached connections in each state, as reported by
+	// connectivity.State; only for transports whose connections report one.
+	SetConnectionStates(states map[string]int)
+	// A connection was not cached, or had expired, and was dialed.
+	ObserveConnectionMiss()
+	// A connection was closed and dropped from the cache; reason is one of
+	// "capacity", "expired" and "failures".
+	ObserveConnectionEviction(reason string)
+}
+
+// discards all events.
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveLookup(time.Duration, int, error) {}
+func (nopMetrics) ObserveForward(string)                   {}
+func (nopMetrics) ObserveFallback()                        {}
+func (nopMetrics) ObserveStabilize(time.Duration, error)   {}
+func (nopMetrics) SetSuccessors(int, int)                  {}
+func (nopMetrics) ObservePredecessorChange()               {}
+func (nopMetrics) ObserveRangeChange()                     {}
+func (nopMetrics) SetConnections(int)                      {}
+func (nopMetrics) SetConnectionStates(map[string]int)      {}
+func (nopMetrics) ObserveConnectionMiss()                  {}
+func (nopMetrics) ObserveConnectionEviction(string)        {}
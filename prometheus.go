@@ -0,0 +1,261 @@
+package concord
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics in the Prometheus text exposition format. It is an http.Handler,
+// serving the metrics of the node it is passed to. Series are not labelled by
+// node, so each node of a process needs an instance of its own, served on a
+// path of its own; passed to several nodes, their gauges would overwrite one
+// another and their counters add up:
+//
+//	metrics := concord.NewPrometheusMetrics()
+//	node := concord.New(concord.Config{Metrics: metrics, ...})
+//	http.Handle("/metrics", metrics)
+type PrometheusMetrics struct {
+	mu sync.Mutex
+
+	lookups           map[string]uint64
+	lookupLatency     histogram
+	lookupHops        histogram
+	forwards          map[string]uint64
+	fallbacks         uint64
+	stabilizeDuration histogram
+	stabilizeFailures uint64
+	successors        map[int]int
+	predecessorChange uint64
+	rangeChanges      uint64
+	connections       int
+	connectionStates  map[string]int
+	connectionMisses  uint64
+	evictions         map[string]uint64
+}
+
+var _ Metrics = (*PrometheusMetrics)(nil)
+
+var (
+	latencyBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	hopBuckets     = []float64{1, 2, 3, 4, 6, 8, 12, 16, 24, 32, 48, 64}
+)
+
+// Creates an empty set of metrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		lookups:           make(map[string]uint64),
+		lookupLatency:     newHistogram(latencyBuckets),
+		lookupHops:        newHistogram(hopBuckets),
+		forwards:          make(map[string]uint64),
+		stabilizeDuration: newHistogram(latencyBuckets),
+		successors:        make(map[int]int),
+		connectionStates:  make(map[string]int),
+		evictions:         make(map[string]uint64),
+	}
+}
+
+func (m *PrometheusMetrics) ObserveLookup(latency time.Duration, hops int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lookupLatency.observe(latency.Seconds())
+	if err != nil {
+		m.lookups["failure"]++
+		return
+	}
+	m.lookups["success"]++
+	m.lookupHops.observe(float64(hops))
+}
+
+func (m *PrometheusMetrics) ObserveForward(peer string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.forwards[peer]++
+}
+
+func (m *PrometheusMetrics) ObserveFallback() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fallbacks++
+}
+
+func (m *PrometheusMetrics) ObserveStabilize(duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stabilizeDuration.observe(duration.Seconds())
+	if err != nil {
+		m.stabilizeFailures++
+	}
+}
+
+func (m *PrometheusMetrics) SetSuccessors(vnode int, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.successors[vnode] = n
+}
+
+func (m *PrometheusMetrics) ObservePredecessorChange() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.predecessorChange++
+}
+
+func (m *PrometheusMetrics) ObserveRangeChange() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rangeChanges++
+}
+
+func (m *PrometheusMetrics) SetConnections(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.connections = n
+}
+
+func (m *PrometheusMetrics) SetConnectionStates(states map[string]int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// states no connection is in any more are reported as zero.
+	for state := range m.connectionStates {
+		m.connectionStates[state] = 0
+	}
+	for state, n := range states {
+		m.connectionStates[state] = n
+	}
+}
+
+func (m *PrometheusMetrics) ObserveConnectionMiss() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.connectionMisses++
+}
+
+func (m *PrometheusMetrics) ObserveConnectionEviction(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictions[reason]++
+}
+
+// Serves the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	bw := bufio.NewWriter(w)
+	m.write(bw)
+	bw.Flush()
+}
+
+func (m *PrometheusMetrics) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header(w, "concord_lookups_total", "counter", "Lookups originating at this node, by result.")
+	for _, result := range slices.Sorted(maps.Keys(m.lookups)) {
+		fmt.Fprintf(w, "concord_lookups_total{result=%s} %d\n", quote(result), m.lookups[result])
+	}
+
+	header(w, "concord_lookup_duration_seconds", "histogram", "Latency of lookups originating at this node.")
+	m.lookupLatency.write(w, "concord_lookup_duration_seconds")
+
+	header(w, "concord_lookup_hops", "histogram", "Nodes that handled each successful lookup, including this one.")
+	m.lookupHops.write(w, "concord_lookup_hops")
+
+	header(w, "concord_forwarded_requests_total", "counter", "Lookup requests forwarded, by peer address.")
+	for _, peer := range slices.Sorted(maps.Keys(m.forwards)) {
+		fmt.Fprintf(w, "concord_forwarded_requests_total{peer=%s} %d\n", quote(peer), m.forwards[peer])
+	}
+
+	header(w, "concord_lookup_fallbacks_total", "counter", "Lookups that fell back to the next contender after one failed.")
+	fmt.Fprintf(w, "concord_lookup_fallbacks_total %d\n", m.fallbacks)
+
+	header(w, "concord_stabilize_duration_seconds", "histogram", "Duration of stabilization rounds.")
+	m.stabilizeDuration.write(w, "concord_stabilize_duration_seconds")
+
+	header(w, "concord_stabilize_failures_total", "counter", "Stabilization rounds that failed to reach a successor or fix a finger.")
+	fmt.Fprintf(w, "concord_stabilize_failures_total %d\n", m.stabilizeFailures)
+
+	header(w, "concord_successors", "gauge", "Length of the successor list, by virtual node.")
+	for _, vnode := range slices.Sorted(maps.Keys(m.successors)) {
+		fmt.Fprintf(w, "concord_successors{vnode=\"%d\"} %d\n", vnode, m.successors[vnode])
+	}
+
+	header(w, "concord_predecessor_changes_total", "counter", "Changes of predecessor.")
+	fmt.Fprintf(w, "concord_predecessor_changes_total %d\n", m.predecessorChange)
+
+	header(w, "concord_range_changes_total", "counter", "Changes of range, each reported to OnRangeChange.")
+	fmt.Fprintf(w, "concord_range_changes_total %d\n", m.rangeChanges)
+
+	header(w, "concord_connection_cache_size", "gauge", "Connections held by the connection cache.")
+	fmt.Fprintf(w, "concord_connection_cache_size %d\n", m.connections)
+
+	header(w, "concord_connection_cache_states", "gauge", "Cached connections, by connectivity state.")
+	for _, state := range slices.Sorted(maps.Keys(m.connectionStates)) {
+		fmt.Fprintf(w, "concord_connection_cache_states{state=%s} %d\n", quote(state), m.connectionStates[state])
+	}
+
+	header(w, "concord_connection_cache_misses_total", "counter", "Connections dialed because they were not cached, or had expired.")
+	fmt.Fprintf(w, "concord_connection_cache_misses_total %d\n", m.connectionMisses)
+
+	header(w, "concord_connection_cache_evictions_total", "counter", "Connections closed and dropped from the cache, by reason.")
+	for _, reason := range slices.Sorted(maps.Keys(m.evictions)) {
+		fmt.Fprintf(w, "concord_connection_cache_evictions_total{reason=%s} %d\n", quote(reason), m.evictions[reason])
+	}
+}
+
+func header(w *bufio.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quotes a label value, escaping as the text format requires.
+func quote(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
+
+// a histogram over fixed upper bounds.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) histogram {
+	return histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(v float64) {
+	if i, _ := slices.BinarySearch(h.bounds, v); i < len(h.bounds) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) write(w *bufio.Writer, name string) {
+	var cumulative uint64
+	for i, b := range h.bounds {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, strconv.FormatFloat(b, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
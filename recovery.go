@@ -0,0 +1,218 @@
+package concord
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Peers remembered per process, beyond the seeds.
+const maxRememberedPeers = 64
+
+// Longest wait between attempts to rejoin the ring while isolated.
+const maxRejoinBackoff = time.Minute
+
+// the peers a host has seen, to rejoin the ring through should it become
+// isolated: the seeds it joined through, and the most recently seen others.
+type peerBook struct {
+	mu    sync.Mutex
+	seeds []string
+	seen  map[string]time.Time
+}
+
+func (b *peerBook) addSeeds(seeds []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seeds = append([]string(nil), seeds...)
+}
+
+// records that a peer at addr was seen at now, forgetting the one seen least
+// recently if there are too many.
+func (b *peerBook) saw(addr string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.seen == nil {
+		b.seen = make(map[string]time.Time)
+	}
+	b.seen[addr] = now
+	if len(b.seen) <= maxRememberedPeers {
+		return
+	}
+
+	var oldest string
+	for a, t := range b.seen {
+		if oldest == "" || t.Before(b.seen[oldest]) {
+			oldest = a
+		}
+	}
+	delete(b.seen, oldest)
+}
+
+// returns the addresses of the seeds and the peers seen, except self; in a
+// stable order, so that simulations are repeatable.
+func (b *peerBook) addresses(self string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	known := map[string]bool{self: true}
+	var addrs []string
+	for _, a := range b.seeds {
+		if !known[a] {
+			known[a] = true
+			addrs = append(addrs, a)
+		}
+	}
+
+	var seen []string
+	for a := range b.seen {
+		if !known[a] {
+			seen = append(seen, a)
+		}
+	}
+	slices.Sort(seen)
+	return append(addrs, seen...)
+}
+
+// records the peers of a ring view, as seen now.
+func (h *host) sawRing(r ring, now time.Time) {
+	for _, s := range r.Successors {
+		if s.Address != h.advAddr {
+			h.peers.saw(s.Address, now)
+		}
+	}
+	if p := r.Predecessor; p != nil && p.Address != h.advAddr {
+		h.peers.saw(p.Address, now)
+	}
+}
+
+// notices when every successor of the process has become one of its own
+// virtual nodes, after it was part of a larger ring: it is cut off from the
+// rest. Rejoining through the peers it knows is then attempted, with backoff,
+// until stabilization finds a successor elsewhere again.
+func (h *host) checkIsolation() {
+	local := true
+	for _, v := range h.vnodes {
+		v.lock.RLock()
+		local = local && v.onlyLocalSuccessors()
+		v.lock.RUnlock()
+	}
+
+	h.isolationLock.Lock()
+	switch {
+	case !local:
+		h.connected = true
+		recovered := h.isolated
+		h.isolated = false
+		h.isolationLock.Unlock()
+
+		if recovered {
+			h.vnodes[0].logger.Info("recovered from isolation")
+			if h.onIsolation != nil {
+				h.onIsolation(false)
+			}
+		}
+
+	case h.connected && !h.isolated:
+		h.isolated = true
+		h.isolationLock.Unlock()
+
+		first := h.vnodes[0]
+		first.logger.Warn("isolated from the rest of the ring; rejoining through known peers")
+		if h.onIsolation != nil {
+			h.onIsolation(true)
+		}
+		h.spawn(first.clock, first.joinBackoff, func() { h.rejoin(first.joinBackoff) })
+
+	default:
+		h.isolationLock.Unlock()
+	}
+}
+
+// forgets about a past isolation, once the process is no longer in the ring.
+func (h *host) resetIsolation() {
+	h.isolationLock.Lock()
+	defer h.isolationLock.Unlock()
+
+	h.connected = false
+	h.isolated = false
+}
+
+// attempts to rejoin the ring while isolated, then schedules the next attempt
+// after backoff.
+func (h *host) rejoin(backoff time.Duration) {
+	h.isolationLock.Lock()
+	isolated := h.isolated
+	h.isolationLock.Unlock()
+	if !isolated || State(h.state.Load()) != StateJoined {
+		return
+	}
+
+	for _, v := range h.vnodes {
+		v.rejoin()
+	}
+
+	first := h.vnodes[0]
+	next := min(2*backoff, max(maxRejoinBackoff, first.joinBackoff))
+	h.spawn(first.clock, backoff, func() { h.rejoin(next) })
+}
+
+// looks up our successor through the peers of the host, if every successor
+// of ours is on this host, and adopts it; stabilization does the rest.
+func (c *Concord) rejoin() {
+	h := c.host
+
+	c.lock.RLock()
+	if !c.setup || !c.onlyLocalSuccessors() {
+		c.lock.RUnlock()
+		return
+	}
+	ctx := c.stabilizeCtx
+	c.lock.RUnlock()
+
+	peers := h.peers.addresses(h.advAddr)
+	for _, i := range h.randPerm(len(peers)) {
+		addr := peers[i]
+		if ctx.Err() != nil {
+			return
+		}
+
+		cli, err := c.clientAddr(addr)
+		if err != nil {
+			continue
+		}
+		resp, err := cli.FindSuccessor(ctx, findReq{id: c.self.Id})
+		if err != nil {
+			c.logger.Debug("failed to rejoin through peer", "peer", addr, "error", err)
+			continue
+		}
+		succ := resp.server
+		if succ.Id == c.self.Id {
+			continue
+		}
+
+		c.lock.Lock()
+		if !c.setup || !c.onlyLocalSuccessors() {
+			c.lock.Unlock()
+			return
+		}
+		c.successors = []Server{succ}
+		c.lock.Unlock()
+
+		c.logger.Info("rejoining ring", "peer", addr, "successor", succ.Name)
+		c.background(func() { c.notifySuccessor(ctx) })
+		return
+	}
+}
+
+// reports whether every successor is on this host. Must be called with the
+// lock held.
+func (c *Concord) onlyLocalSuccessors() bool {
+	for _, s := range c.successors {
+		if s.Address != c.host.advAddr {
+			return false
+		}
+	}
+	return true
+}
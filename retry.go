@@ -0,0 +1,80 @@
+package concord
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// How requests to other nodes are retried when they fail.
+type RetryPolicy struct {
+	// Attempts per request, including the first one; 1 disables retries.
+	Attempts uint
+	// Wait before the first retry, doubling with each one up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Fraction of each wait that is random; with 0.2, waits are between 80%
+	// and 120% of the backoff.
+	Jitter float64
+	// Reports whether a failed request may be retried. By default, requests
+	// that failed with codes.Unavailable or codes.DeadlineExceeded are.
+	Retryable func(err error) bool
+}
+
+// returns the wait before retry n, counting from 1, given a random number in
+// [0, 1).
+func (p *RetryPolicy) backoff(n uint, rnd float64) time.Duration {
+	d := p.Backoff
+	for i := uint(1); i < n && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+
+	return time.Duration(float64(d) * (1 + p.Jitter*(2*rnd-1)))
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// the cause of a request's context once its timeout ran out, telling it apart
+// from the caller's own deadline.
+var errRPCTimeout = errors.New("concord: request timed out")
+
+// a connection bounding each request by a timeout, and retrying those that
+// fail as the policy allows. The timeout is a context deadline on the wall
+// clock, not a timer of the node's clock: a request blocks the call sending it,
+// and a virtual clock running calls one at a time could not fire a timer while
+// it waits. Simulated networks time out lost requests themselves.
+type retryConn struct {
+	grpc.ClientConnInterface
+	timeout time.Duration
+	policy  *RetryPolicy
+	clock   Clock
+	host    *host
+}
+
+func (c *retryConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	for attempt := uint(1); ; attempt++ {
+		actx, cancel := context.WithTimeoutCause(ctx, c.timeout, errRPCTimeout)
+		err := c.ClientConnInterface.Invoke(actx, method, args, reply, opts...)
+		cancel()
+
+		if err == nil || attempt >= c.policy.Attempts || !c.policy.Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := c.policy.backoff(attempt, c.host.randFloat64())
+		if c.clock.Sleep(ctx, wait) != nil {
+			return err
+		}
+	}
+}
@@ -2,90 +2,103 @@ package concord
 
 import (
 	"context"
-	"crypto/tls"
-	"sync"
-	"time"
 
 	"github.com/ollelogdahl/concord/rpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 //go:generate protoc --proto_path=../ --go_out=../ --go-grpc_out=../ ../proto/concord.proto
 
-type cachedConn struct {
-	rpc       rpcClient
-	createdAt time.Time
-}
+// metadata key naming the virtual node a request is meant for.
+const targetKey = "concord-target"
 
-type connectionCache struct {
-	mu    sync.RWMutex
-	conns map[string]cachedConn
-	ttl   time.Duration
+type rpcHandler struct {
+	rpc.UnimplementedChordServiceServer
+	concord *Concord
 }
 
-func newConnectionCache(ttl time.Duration) connectionCache {
-	return connectionCache{
-		conns: make(map[string]cachedConn),
-		ttl:   ttl,
-	}
+func (r *rpcHandler) RegisterService(srv grpc.ServiceRegistrar) {
+	rpc.RegisterChordServiceServer(srv, r)
 }
 
-func (cc *connectionCache) get(addr string, tls *tls.Config) (rpcClient, error) {
-	cc.mu.RLock()
-	cached, exists := cc.conns[addr]
-	cc.mu.RUnlock()
+// returns the virtual node targeted by the request; the first one if the
+// request names none.
+func (r *rpcHandler) node(ctx context.Context) (*Concord, error) {
+	if !r.concord.host.serving.Load() {
+		return nil, status.Error(codes.Unavailable, "node stopped")
+	}
 
-	if exists && time.Since(cached.createdAt) < cc.ttl {
-		return cached.rpc, nil
+	md, _ := metadata.FromIncomingContext(ctx)
+	targets := md.Get(targetKey)
+	if len(targets) == 0 {
+		return r.concord, nil
 	}
 
-	cli, err := newClientGrpc(addr, tls)
+	id, err := parseID(targets[0])
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "invalid target %q", targets[0])
 	}
+	for _, v := range r.concord.host.vnodes {
+		if v.self.Id == id {
+			return v, nil
+		}
+	}
+	return nil, toStatus(ErrNotReady)
+}
 
-	cc.mu.Lock()
-	cc.conns[addr] = cachedConn{rpc: cli, createdAt: time.Now()}
-	cc.mu.Unlock()
+func (r *rpcHandler) FindSuccessor(ctx context.Context, req *rpc.FindReq) (_ *rpc.FindResp, err error) {
+	ctx, span := r.concord.host.tracing.startServer(ctx, rpc.ChordService_FindSuccessor_FullMethodName)
+	defer func() { endSpan(span, err) }()
 
-	return cli, nil
-}
+	c, err := r.node(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func (cc *connectionCache) invalidate() {
-	cc.mu.Lock()
-	defer cc.mu.Unlock()
-	for k := range cc.conns {
-		delete(cc.conns, k)
+	fr, err := c.lookup(ctx, convertProtoToFindReq(req))
+	if err != nil {
+		return nil, toStatus(err)
 	}
-}
 
-type rpcHandler struct {
-	rpc.UnimplementedChordServiceServer
-	concord *Concord
+	return convertFindRespToProto(fr), nil
 }
 
-func (r *rpcHandler) RegisterService(srv *grpc.Server) {
-	rpc.RegisterChordServiceServer(srv, r)
-}
+func (r *rpcHandler) ClosestPreceding(ctx context.Context, req *rpc.FindReq) (_ *rpc.Step, err error) {
+	ctx, span := r.concord.host.tracing.startServer(ctx, rpc.ChordService_ClosestPreceding_FullMethodName)
+	defer func() { endSpan(span, err) }()
 
-func (r *rpcHandler) FindSuccessor(ctx context.Context, req *rpc.FindReq) (*rpc.FindResp, error) {
-	s, err := r.concord.findSuccessor(ctx, req.Id)
+	c, err := r.node(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := &rpc.FindResp{}
-	resp.Server = convertServerToProto(&s)
+	step, err := c.closestPreceding(convertProtoToFindReq(req))
+	if err != nil {
+		return nil, toStatus(err)
+	}
 
-	return resp, nil
+	return convertStepToProto(step), nil
 }
 
-func (r *rpcHandler) GetRing(ctx context.Context, _ *emptypb.Empty) (*rpc.Ring, error) {
-	succ := r.concord.Successors()
-	pred, ok := r.concord.Predecessor()
+func (r *rpcHandler) GetRing(ctx context.Context, _ *emptypb.Empty) (_ *rpc.Ring, err error) {
+	ctx, span := r.concord.host.tracing.startServer(ctx, rpc.ChordService_GetRing_FullMethodName)
+	defer func() { endSpan(span, err) }()
+
+	c, err := r.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !c.ready() {
+		return nil, toStatus(ErrNotReady)
+	}
+
+	succ := c.Successors()
+	pred, ok := c.Predecessor()
 
 	protoSuccs := make([]*rpc.Server, len(succ))
 	for i, s := range succ {
@@ -103,49 +116,70 @@ func (r *rpcHandler) GetRing(ctx context.Context, _ *emptypb.Empty) (*rpc.Ring,
 	return resp, nil
 }
 
-func (r *rpcHandler) Notify(ctx context.Context, srv *rpc.Server) (*emptypb.Empty, error) {
-	r.concord.rectify(ctx, *convertProtoToServer(srv))
+func (r *rpcHandler) Notify(ctx context.Context, srv *rpc.Server) (_ *emptypb.Empty, err error) {
+	ctx, span := r.concord.host.tracing.startServer(ctx, rpc.ChordService_Notify_FullMethodName)
+	defer func() { endSpan(span, err) }()
+
+	c, err := r.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.rectify(ctx, *convertProtoToServer(srv))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (r *rpcHandler) Leave(ctx context.Context, req *rpc.LeaveReq) (_ *emptypb.Empty, err error) {
+	ctx, span := r.concord.host.tracing.startServer(ctx, rpc.ChordService_Leave_FullMethodName)
+	defer func() { endSpan(span, err) }()
+
+	c, err := r.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Server == nil {
+		return nil, status.Error(codes.InvalidArgument, "leave names no server")
+	}
+
+	lr := ring{
+		Predecessor: convertProtoToServer(req.Predecessor),
+		Successors:  make([]Server, len(req.Successors)),
+	}
+	for i := range req.Successors {
+		lr.Successors[i] = *convertProtoToServer(req.Successors[i])
+	}
+
+	c.spliceOut(*convertProtoToServer(req.Server), lr)
 
 	return &emptypb.Empty{}, nil
 }
 
 type rpcClient interface {
-	FindSuccessor(ctx context.Context, id uint64) (Server, error)
+	FindSuccessor(ctx context.Context, req findReq) (findResp, error)
+	ClosestPreceding(ctx context.Context, req findReq) (lookupStep, error)
 	GetRing(ctx context.Context) (ring, error)
 	Notify(ctx context.Context, srv Server) error
+	Leave(ctx context.Context, srv Server, r ring) error
+
+	// returns a client whose requests are routed to the virtual node id.
+	withTarget(id ID) rpcClient
 }
 
 type rpcClientGrpc struct {
-	conn *grpc.ClientConn
-	cli  rpc.ChordServiceClient
+	cli    rpc.ChordServiceClient
+	target string
 }
 
 type rpcClientDispatch struct {
-	hnd *rpcHandler
+	hnd    *rpcHandler
+	target string
 }
 
-func newClientGrpc(addr string, tls *tls.Config) (rpcClient, error) {
-
-	var creds credentials.TransportCredentials
-	if tls == nil {
-		creds = insecure.NewCredentials()
-	} else {
-		creds = credentials.NewTLS(tls)
-	}
-
-	conn, err := grpc.NewClient(addr,
-		grpc.WithTransportCredentials(creds),
-	)
-	if err != nil {
-		return &rpcClientGrpc{}, err
-	}
-
-	cli := rpc.NewChordServiceClient(conn)
-
+func newClientGrpc(conn grpc.ClientConnInterface) rpcClient {
 	return &rpcClientGrpc{
-		conn: conn,
-		cli:  cli,
-	}, nil
+		cli: rpc.NewChordServiceClient(conn),
+	}
 }
 
 func newClientDispatch(hnd *rpcHandler) rpcClient {
@@ -154,19 +188,43 @@ func newClientDispatch(hnd *rpcHandler) rpcClient {
 	}
 }
 
-func (c *rpcClientGrpc) FindSuccessor(ctx context.Context, id uint64) (Server, error) {
-	req := rpc.FindReq{Id: id}
-	resp, err := c.cli.FindSuccessor(ctx, &req)
+func (c *rpcClientGrpc) withTarget(id ID) rpcClient {
+	cc := *c
+	cc.target = id.String()
+	return &cc
+}
+
+func (c *rpcClientGrpc) outgoing(ctx context.Context) context.Context {
+	if c.target == "" {
+		return ctx
+	}
+	// replace, rather than append to, a target the context may already carry.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(targetKey, c.target)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (c *rpcClientGrpc) FindSuccessor(ctx context.Context, req findReq) (findResp, error) {
+	resp, err := c.cli.FindSuccessor(c.outgoing(ctx), convertFindReqToProto(req))
 	if err != nil {
-		return Server{}, err
+		return findResp{}, fromStatus(err)
 	}
 
-	return *convertProtoToServer(resp.Server), nil
+	return convertProtoToFindResp(resp), nil
+}
+func (c *rpcClientGrpc) ClosestPreceding(ctx context.Context, req findReq) (lookupStep, error) {
+	resp, err := c.cli.ClosestPreceding(c.outgoing(ctx), convertFindReqToProto(req))
+	if err != nil {
+		return lookupStep{}, fromStatus(err)
+	}
+
+	return convertProtoToStep(resp), nil
 }
 func (c *rpcClientGrpc) GetRing(ctx context.Context) (ring, error) {
-	resp, err := c.cli.GetRing(ctx, &emptypb.Empty{})
+	resp, err := c.cli.GetRing(c.outgoing(ctx), &emptypb.Empty{})
 	if err != nil {
-		return ring{}, err
+		return ring{}, fromStatus(err)
 	}
 
 	r := ring{
@@ -183,27 +241,58 @@ func (c *rpcClientGrpc) GetRing(ctx context.Context) (ring, error) {
 func (c *rpcClientGrpc) Notify(ctx context.Context, srv Server) error {
 	req := convertServerToProto(&srv)
 
-	_, err := c.cli.Notify(ctx, req)
+	_, err := c.cli.Notify(c.outgoing(ctx), req)
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	return nil
+}
+
+func (c *rpcClientGrpc) Leave(ctx context.Context, srv Server, r ring) error {
+	_, err := c.cli.Leave(c.outgoing(ctx), convertLeaveToProto(srv, r))
 	if err != nil {
-		return err
+		return fromStatus(err)
 	}
 
 	return nil
 }
 
-func (c *rpcClientDispatch) FindSuccessor(ctx context.Context, id uint64) (Server, error) {
-	req := rpc.FindReq{Id: id}
-	resp, err := c.hnd.FindSuccessor(ctx, &req)
+func (c *rpcClientDispatch) withTarget(id ID) rpcClient {
+	cc := *c
+	cc.target = id.String()
+	return &cc
+}
+
+// replaces the metadata of ctx, as if the request arrived over the network.
+func (c *rpcClientDispatch) incoming(ctx context.Context) context.Context {
+	md := metadata.MD{}
+	if c.target != "" {
+		md.Set(targetKey, c.target)
+	}
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func (c *rpcClientDispatch) FindSuccessor(ctx context.Context, req findReq) (findResp, error) {
+	resp, err := c.hnd.FindSuccessor(c.incoming(ctx), convertFindReqToProto(req))
 	if err != nil {
-		return Server{}, err
+		return findResp{}, fromStatus(err)
 	}
 
-	return *convertProtoToServer(resp.Server), nil
+	return convertProtoToFindResp(resp), nil
+}
+func (c *rpcClientDispatch) ClosestPreceding(ctx context.Context, req findReq) (lookupStep, error) {
+	resp, err := c.hnd.ClosestPreceding(c.incoming(ctx), convertFindReqToProto(req))
+	if err != nil {
+		return lookupStep{}, fromStatus(err)
+	}
+
+	return convertProtoToStep(resp), nil
 }
 func (c *rpcClientDispatch) GetRing(ctx context.Context) (ring, error) {
-	resp, err := c.hnd.GetRing(ctx, &emptypb.Empty{})
+	resp, err := c.hnd.GetRing(c.incoming(ctx), &emptypb.Empty{})
 	if err != nil {
-		return ring{}, err
+		return ring{}, fromStatus(err)
 	}
 
 	r := ring{}
@@ -221,23 +310,140 @@ func (c *rpcClientDispatch) GetRing(ctx context.Context) (ring, error) {
 func (c *rpcClientDispatch) Notify(ctx context.Context, srv Server) error {
 	req := convertServerToProto(&srv)
 
-	_, err := c.hnd.Notify(ctx, req)
+	_, err := c.hnd.Notify(c.incoming(ctx), req)
 	if err != nil {
-		return err
+		return fromStatus(err)
 	}
 
 	return nil
 }
 
+func (c *rpcClientDispatch) Leave(ctx context.Context, srv Server, r ring) error {
+	_, err := c.hnd.Leave(c.incoming(ctx), convertLeaveToProto(srv, r))
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	return nil
+}
+
+func convertFindReqToProto(req findReq) *rpc.FindReq {
+	pr := &rpc.FindReq{
+		Trace:   req.trace,
+		Hops:    uint32(req.hops),
+		Visited: make([]uint64, len(req.visited)),
+	}
+	pr.Id, pr.WideId = convertIDToProto(req.id)
+
+	wide := false
+	wideVisited := make([][]byte, len(req.visited))
+	for i, id := range req.visited {
+		pr.Visited[i], wideVisited[i] = convertIDToProto(id)
+		wide = wide || wideVisited[i] != nil
+	}
+	if wide {
+		pr.WideVisited = wideVisited
+	}
+	return pr
+}
+
+func convertProtoToFindReq(req *rpc.FindReq) findReq {
+	fr := findReq{
+		id:      convertProtoToID(req.Id, req.WideId),
+		trace:   req.Trace,
+		hops:    uint(req.Hops),
+		visited: make([]ID, len(req.Visited)),
+	}
+	for i, v := range req.Visited {
+		var wide []byte
+		if i < len(req.WideVisited) {
+			wide = req.WideVisited[i]
+		}
+		fr.visited[i] = convertProtoToID(v, wide)
+	}
+	return fr
+}
+
+func convertFindRespToProto(resp findResp) *rpc.FindResp {
+	pr := &rpc.FindResp{
+		Server:   convertServerToProto(&resp.server),
+		Hops:     make([]*rpc.Hop, len(resp.hops)),
+		HopCount: uint32(resp.hopCount),
+	}
+	for i, h := range resp.hops {
+		pr.Hops[i] = &rpc.Hop{
+			Server:  convertServerToProto(&h.Node),
+			Finger:  int32(h.Finger),
+			Latency: durationpb.New(h.Latency),
+		}
+	}
+	return pr
+}
+
+func convertProtoToFindResp(resp *rpc.FindResp) findResp {
+	fr := findResp{
+		server:   *convertProtoToServer(resp.Server),
+		hops:     make([]Hop, len(resp.Hops)),
+		hopCount: uint(resp.HopCount),
+	}
+	for i, h := range resp.Hops {
+		fr.hops[i] = Hop{
+			Node:    *convertProtoToServer(h.Server),
+			Finger:  int(h.Finger),
+			Latency: h.Latency.AsDuration(),
+		}
+	}
+	return fr
+}
+
+func convertStepToProto(step lookupStep) *rpc.Step {
+	ps := &rpc.Step{
+		Resolved:   step.resolved,
+		Server:     convertServerToProto(&step.server),
+		Finger:     int32(step.finger),
+		Successors: make([]*rpc.Server, len(step.successors)),
+	}
+	for i, s := range step.successors {
+		ps.Successors[i] = convertServerToProto(&s)
+	}
+	return ps
+}
+
+func convertProtoToStep(step *rpc.Step) lookupStep {
+	ls := lookupStep{
+		resolved:   step.Resolved,
+		server:     *convertProtoToServer(step.Server),
+		finger:     int(step.Finger),
+		successors: make([]Server, len(step.Successors)),
+	}
+	for i, s := range step.Successors {
+		ls.successors[i] = *convertProtoToServer(s)
+	}
+	return ls
+}
+
+func convertLeaveToProto(srv Server, r ring) *rpc.LeaveReq {
+	req := &rpc.LeaveReq{
+		Server:      convertServerToProto(&srv),
+		Predecessor: convertServerToProto(r.Predecessor),
+		Successors:  make([]*rpc.Server, len(r.Successors)),
+	}
+	for i, s := range r.Successors {
+		req.Successors[i] = convertServerToProto(&s)
+	}
+	return req
+}
+
 func convertServerToProto(server *Server) *rpc.Server {
 	if server == nil {
 		return nil
 	}
-	return &rpc.Server{
-		Id:      server.Id,
+	ps := &rpc.Server{
 		Name:    server.Name,
 		Address: server.Address,
 	}
+	ps.Id, ps.WideId = convertIDToProto(server.Id)
+	return ps
 }
 
 func convertProtoToServer(server *rpc.Server) *Server {
@@ -245,8 +451,23 @@ func convertProtoToServer(server *rpc.Server) *Server {
 		return nil
 	}
 	return &Server{
-		Id:      server.Id,
+		Id:      convertProtoToID(server.Id, server.WideId),
 		Name:    server.Name,
 		Address: server.Address,
 	}
 }
+
+// splits an id into its lowest 64 bits, and its bytes if it is any wider.
+func convertIDToProto(id ID) (uint64, []byte) {
+	if id.isUint64() {
+		return id.Uint64(), nil
+	}
+	return id.Uint64(), id.Bytes()
+}
+
+func convertProtoToID(id uint64, wide []byte) ID {
+	if len(wide) > 0 {
+		return IDFromBytes(wide)
+	}
+	return IDFromUint64(id)
+}
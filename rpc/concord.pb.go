@@ -9,6 +9,7 @@ package rpc
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
@@ -21,12 +22,19 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ids wider than 64 bits are sent as big-endian bytes in the wide_ fields;
+// the uint64 fields then hold their lowest 64 bits.
 type FindReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id          uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Trace       bool     `protobuf:"varint,2,opt,name=trace,proto3" json:"trace,omitempty"`
+	Hops        uint32   `protobuf:"varint,3,opt,name=hops,proto3" json:"hops,omitempty"`
+	Visited     []uint64 `protobuf:"varint,4,rep,packed,name=visited,proto3" json:"visited,omitempty"`
+	WideId      []byte   `protobuf:"bytes,5,opt,name=wide_id,json=wideId,proto3" json:"wide_id,omitempty"`
+	WideVisited [][]byte `protobuf:"bytes,6,rep,name=wide_visited,json=wideVisited,proto3" json:"wide_visited,omitempty"`
 }
 
 func (x *FindReq) Reset() {
@@ -66,12 +74,50 @@ func (x *FindReq) GetId() uint64 {
 	return 0
 }
 
+func (x *FindReq) GetTrace() bool {
+	if x != nil {
+		return x.Trace
+	}
+	return false
+}
+
+func (x *FindReq) GetHops() uint32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
+func (x *FindReq) GetVisited() []uint64 {
+	if x != nil {
+		return x.Visited
+	}
+	return nil
+}
+
+func (x *FindReq) GetWideId() []byte {
+	if x != nil {
+		return x.WideId
+	}
+	return nil
+}
+
+func (x *FindReq) GetWideVisited() [][]byte {
+	if x != nil {
+		return x.WideVisited
+	}
+	return nil
+}
+
 type FindResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Server *Server `protobuf:"bytes,1,opt,name=server,proto3,oneof" json:"server,omitempty"`
+	Hops   []*Hop  `protobuf:"bytes,2,rep,name=hops,proto3" json:"hops,omitempty"`
+	// the number of nodes that handled the lookup, traced or not.
+	HopCount uint32 `protobuf:"varint,3,opt,name=hop_count,json=hopCount,proto3" json:"hop_count,omitempty"`
 }
 
 func (x *FindResp) Reset() {
@@ -111,6 +157,150 @@ func (x *FindResp) GetServer() *Server {
 	return nil
 }
 
+func (x *FindResp) GetHops() []*Hop {
+	if x != nil {
+		return x.Hops
+	}
+	return nil
+}
+
+func (x *FindResp) GetHopCount() uint32 {
+	if x != nil {
+		return x.HopCount
+	}
+	return 0
+}
+
+type Hop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server  *Server              `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Finger  int32                `protobuf:"varint,2,opt,name=finger,proto3" json:"finger,omitempty"`
+	Latency *durationpb.Duration `protobuf:"bytes,3,opt,name=latency,proto3" json:"latency,omitempty"`
+}
+
+func (x *Hop) Reset() {
+	*x = Hop{}
+	mi := &file_proto_concord_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hop) ProtoMessage() {}
+
+func (x *Hop) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hop.ProtoReflect.Descriptor instead.
+func (*Hop) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Hop) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *Hop) GetFinger() int32 {
+	if x != nil {
+		return x.Finger
+	}
+	return 0
+}
+
+func (x *Hop) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+type Step struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Resolved   bool      `protobuf:"varint,1,opt,name=resolved,proto3" json:"resolved,omitempty"`
+	Server     *Server   `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	Finger     int32     `protobuf:"varint,3,opt,name=finger,proto3" json:"finger,omitempty"`
+	Successors []*Server `protobuf:"bytes,4,rep,name=successors,proto3" json:"successors,omitempty"`
+}
+
+func (x *Step) Reset() {
+	*x = Step{}
+	mi := &file_proto_concord_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Step) ProtoMessage() {}
+
+func (x *Step) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Step.ProtoReflect.Descriptor instead.
+func (*Step) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Step) GetResolved() bool {
+	if x != nil {
+		return x.Resolved
+	}
+	return false
+}
+
+func (x *Step) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *Step) GetFinger() int32 {
+	if x != nil {
+		return x.Finger
+	}
+	return 0
+}
+
+func (x *Step) GetSuccessors() []*Server {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
 type Server struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -119,11 +309,12 @@ type Server struct {
 	Id      uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	WideId  []byte `protobuf:"bytes,4,opt,name=wide_id,json=wideId,proto3" json:"wide_id,omitempty"`
 }
 
 func (x *Server) Reset() {
 	*x = Server{}
-	mi := &file_proto_concord_proto_msgTypes[2]
+	mi := &file_proto_concord_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -135,7 +326,7 @@ func (x *Server) String() string {
 func (*Server) ProtoMessage() {}
 
 func (x *Server) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_concord_proto_msgTypes[2]
+	mi := &file_proto_concord_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -148,7 +339,7 @@ func (x *Server) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Server.ProtoReflect.Descriptor instead.
 func (*Server) Descriptor() ([]byte, []int) {
-	return file_proto_concord_proto_rawDescGZIP(), []int{2}
+	return file_proto_concord_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Server) GetId() uint64 {
@@ -172,6 +363,13 @@ func (x *Server) GetAddress() string {
 	return ""
 }
 
+func (x *Server) GetWideId() []byte {
+	if x != nil {
+		return x.WideId
+	}
+	return nil
+}
+
 type Ring struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -183,7 +381,7 @@ type Ring struct {
 
 func (x *Ring) Reset() {
 	*x = Ring{}
-	mi := &file_proto_concord_proto_msgTypes[3]
+	mi := &file_proto_concord_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -195,7 +393,7 @@ func (x *Ring) String() string {
 func (*Ring) ProtoMessage() {}
 
 func (x *Ring) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_concord_proto_msgTypes[3]
+	mi := &file_proto_concord_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -208,7 +406,7 @@ func (x *Ring) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ring.ProtoReflect.Descriptor instead.
 func (*Ring) Descriptor() ([]byte, []int) {
-	return file_proto_concord_proto_rawDescGZIP(), []int{3}
+	return file_proto_concord_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Ring) GetPredecessor() *Server {
@@ -225,44 +423,647 @@ func (x *Ring) GetSuccessors() []*Server {
 	return nil
 }
 
+type LeaveReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Server      *Server   `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Predecessor *Server   `protobuf:"bytes,2,opt,name=predecessor,proto3,oneof" json:"predecessor,omitempty"`
+	Successors  []*Server `protobuf:"bytes,3,rep,name=successors,proto3" json:"successors,omitempty"`
+}
+
+func (x *LeaveReq) Reset() {
+	*x = LeaveReq{}
+	mi := &file_proto_concord_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveReq) ProtoMessage() {}
+
+func (x *LeaveReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveReq.ProtoReflect.Descriptor instead.
+func (*LeaveReq) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LeaveReq) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *LeaveReq) GetPredecessor() *Server {
+	if x != nil {
+		return x.Predecessor
+	}
+	return nil
+}
+
+func (x *LeaveReq) GetSuccessors() []*Server {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
+type Range struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start     uint64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End       uint64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	WideStart []byte `protobuf:"bytes,3,opt,name=wide_start,json=wideStart,proto3" json:"wide_start,omitempty"`
+	WideEnd   []byte `protobuf:"bytes,4,opt,name=wide_end,json=wideEnd,proto3" json:"wide_end,omitempty"`
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	mi := &file_proto_concord_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Range) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Range) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Range) GetWideStart() []byte {
+	if x != nil {
+		return x.WideStart
+	}
+	return nil
+}
+
+func (x *Range) GetWideEnd() []byte {
+	if x != nil {
+		return x.WideEnd
+	}
+	return nil
+}
+
+type Finger struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start     uint64  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	Node      *Server `protobuf:"bytes,2,opt,name=node,proto3,oneof" json:"node,omitempty"`
+	WideStart []byte  `protobuf:"bytes,3,opt,name=wide_start,json=wideStart,proto3" json:"wide_start,omitempty"`
+}
+
+func (x *Finger) Reset() {
+	*x = Finger{}
+	mi := &file_proto_concord_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finger) ProtoMessage() {}
+
+func (x *Finger) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finger.ProtoReflect.Descriptor instead.
+func (*Finger) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Finger) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Finger) GetNode() *Server {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *Finger) GetWideStart() []byte {
+	if x != nil {
+		return x.WideStart
+	}
+	return nil
+}
+
+type VirtualNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Self        *Server   `protobuf:"bytes,1,opt,name=self,proto3" json:"self,omitempty"`
+	Ready       bool      `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	Range       *Range    `protobuf:"bytes,3,opt,name=range,proto3" json:"range,omitempty"`
+	Predecessor *Server   `protobuf:"bytes,4,opt,name=predecessor,proto3,oneof" json:"predecessor,omitempty"`
+	Successors  []*Server `protobuf:"bytes,5,rep,name=successors,proto3" json:"successors,omitempty"`
+	Fingers     []*Finger `protobuf:"bytes,6,rep,name=fingers,proto3" json:"fingers,omitempty"`
+}
+
+func (x *VirtualNode) Reset() {
+	*x = VirtualNode{}
+	mi := &file_proto_concord_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VirtualNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualNode) ProtoMessage() {}
+
+func (x *VirtualNode) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualNode.ProtoReflect.Descriptor instead.
+func (*VirtualNode) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VirtualNode) GetSelf() *Server {
+	if x != nil {
+		return x.Self
+	}
+	return nil
+}
+
+func (x *VirtualNode) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *VirtualNode) GetRange() *Range {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *VirtualNode) GetPredecessor() *Server {
+	if x != nil {
+		return x.Predecessor
+	}
+	return nil
+}
+
+func (x *VirtualNode) GetSuccessors() []*Server {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
+func (x *VirtualNode) GetFingers() []*Finger {
+	if x != nil {
+		return x.Fingers
+	}
+	return nil
+}
+
+type Info struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name              string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address           string               `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	VirtualNodes      []*VirtualNode       `protobuf:"bytes,3,rep,name=virtual_nodes,json=virtualNodes,proto3" json:"virtual_nodes,omitempty"`
+	HashBits          uint32               `protobuf:"varint,4,opt,name=hash_bits,json=hashBits,proto3" json:"hash_bits,omitempty"`
+	SuccessorCount    uint32               `protobuf:"varint,5,opt,name=successor_count,json=successorCount,proto3" json:"successor_count,omitempty"`
+	StabilizeInterval *durationpb.Duration `protobuf:"bytes,6,opt,name=stabilize_interval,json=stabilizeInterval,proto3" json:"stabilize_interval,omitempty"`
+	Uptime            *durationpb.Duration `protobuf:"bytes,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Version           string               `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *Info) Reset() {
+	*x = Info{}
+	mi := &file_proto_concord_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Info) ProtoMessage() {}
+
+func (x *Info) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Info.ProtoReflect.Descriptor instead.
+func (*Info) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Info) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Info) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Info) GetVirtualNodes() []*VirtualNode {
+	if x != nil {
+		return x.VirtualNodes
+	}
+	return nil
+}
+
+func (x *Info) GetHashBits() uint32 {
+	if x != nil {
+		return x.HashBits
+	}
+	return 0
+}
+
+func (x *Info) GetSuccessorCount() uint32 {
+	if x != nil {
+		return x.SuccessorCount
+	}
+	return 0
+}
+
+func (x *Info) GetStabilizeInterval() *durationpb.Duration {
+	if x != nil {
+		return x.StabilizeInterval
+	}
+	return nil
+}
+
+func (x *Info) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *Info) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type Stats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lookups           uint64 `protobuf:"varint,1,opt,name=lookups,proto3" json:"lookups,omitempty"`
+	LookupFailures    uint64 `protobuf:"varint,2,opt,name=lookup_failures,json=lookupFailures,proto3" json:"lookup_failures,omitempty"`
+	Forwards          uint64 `protobuf:"varint,3,opt,name=forwards,proto3" json:"forwards,omitempty"`
+	StabilizeFailures uint64 `protobuf:"varint,4,opt,name=stabilize_failures,json=stabilizeFailures,proto3" json:"stabilize_failures,omitempty"`
+	Suspicions        uint64 `protobuf:"varint,5,opt,name=suspicions,proto3" json:"suspicions,omitempty"`
+	Deaths            uint64 `protobuf:"varint,6,opt,name=deaths,proto3" json:"deaths,omitempty"`
+	FailureDetector   string `protobuf:"bytes,7,opt,name=failure_detector,json=failureDetector,proto3" json:"failure_detector,omitempty"`
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_proto_concord_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_concord_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_proto_concord_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Stats) GetLookups() uint64 {
+	if x != nil {
+		return x.Lookups
+	}
+	return 0
+}
+
+func (x *Stats) GetLookupFailures() uint64 {
+	if x != nil {
+		return x.LookupFailures
+	}
+	return 0
+}
+
+func (x *Stats) GetForwards() uint64 {
+	if x != nil {
+		return x.Forwards
+	}
+	return 0
+}
+
+func (x *Stats) GetStabilizeFailures() uint64 {
+	if x != nil {
+		return x.StabilizeFailures
+	}
+	return 0
+}
+
+func (x *Stats) GetSuspicions() uint64 {
+	if x != nil {
+		return x.Suspicions
+	}
+	return 0
+}
+
+func (x *Stats) GetDeaths() uint64 {
+	if x != nil {
+		return x.Deaths
+	}
+	return 0
+}
+
+func (x *Stats) GetFailureDetector() string {
+	if x != nil {
+		return x.FailureDetector
+	}
+	return ""
+}
+
 var File_proto_concord_proto protoreflect.FileDescriptor
 
 var file_proto_concord_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x1a, 0x1b,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
-	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x19, 0x0a, 0x07, 0x46,
-	0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22, 0x43, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65,
-	0x73, 0x70, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x88, 0x01, 0x01,
-	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x22, 0x46, 0x0a, 0x06, 0x53,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x22, 0x7f, 0x0a, 0x04, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x36, 0x0a, 0x0b, 0x70,
-	0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72,
-	0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72,
-	0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x6f, 0x72, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65,
-	0x73, 0x73, 0x6f, 0x72, 0x32, 0xa9, 0x01, 0x0a, 0x0c, 0x43, 0x68, 0x6f, 0x72, 0x64, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x64, 0x53, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x12, 0x10, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64,
-	0x2e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f,
-	0x72, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x12, 0x30, 0x0a, 0x07, 0x47,
-	0x65, 0x74, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0d,
-	0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a,
-	0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72,
-	0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x42, 0x07, 0x5a, 0x05, 0x2e, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x99, 0x01, 0x0a, 0x07,
+	0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x68, 0x6f, 0x70,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x69, 0x73, 0x69, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x04, 0x52, 0x07, 0x76, 0x69, 0x73, 0x69, 0x74, 0x65, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x77,
+	0x69, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x77, 0x69,
+	0x64, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x64, 0x65, 0x5f, 0x76, 0x69, 0x73,
+	0x69, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x77, 0x69, 0x64, 0x65,
+	0x56, 0x69, 0x73, 0x69, 0x74, 0x65, 0x64, 0x22, 0x82, 0x01, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x2c, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x88,
+	0x01, 0x01, 0x12, 0x20, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x48, 0x6f, 0x70, 0x52, 0x04,
+	0x68, 0x6f, 0x70, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x22, 0x7b, 0x0a, 0x03,
+	0x48, 0x6f, 0x70, 0x12, 0x27, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x94, 0x01, 0x0a, 0x04, 0x53, 0x74,
+	0x65, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x12, 0x27,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52,
+	0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x6e, 0x67, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x12,
+	0x2f, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73,
+	0x22, 0x5f, 0x0a, 0x06, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x77, 0x69, 0x64, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x77, 0x69, 0x64, 0x65, 0x49,
+	0x64, 0x22, 0x7f, 0x0a, 0x04, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x36, 0x0a, 0x0b, 0x70, 0x72, 0x65,
+	0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x48,
+	0x00, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x88, 0x01,
+	0x01, 0x12, 0x2f, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f,
+	0x72, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73,
+	0x6f, 0x72, 0x22, 0xac, 0x01, 0x0a, 0x08, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x52, 0x65, 0x71, 0x12,
+	0x27, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x64,
+	0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x48, 0x00,
+	0x52, 0x0b, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x2f, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72,
+	0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f,
+	0x72, 0x22, 0x69, 0x0a, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65,
+	0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x77, 0x69, 0x64, 0x65, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x77, 0x69, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x77, 0x69, 0x64, 0x65, 0x45, 0x6e, 0x64, 0x22, 0x70, 0x0a, 0x06,
+	0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x28, 0x0a, 0x04,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e,
+	0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x04, 0x6e,
+	0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x64, 0x65, 0x5f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x77, 0x69, 0x64, 0x65,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x22, 0x92,
+	0x02, 0x0a, 0x0b, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x23,
+	0x0a, 0x04, 0x73, 0x65, 0x6c, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63,
+	0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x04, 0x73,
+	0x65, 0x6c, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x12, 0x24, 0x0a, 0x05, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f,
+	0x72, 0x64, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x36, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65,
+	0x73, 0x73, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f,
+	0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x0a, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x12, 0x29, 0x0a, 0x07, 0x66, 0x69, 0x6e, 0x67,
+	0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63,
+	0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x52, 0x07, 0x66, 0x69, 0x6e, 0x67,
+	0x65, 0x72, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73,
+	0x73, 0x6f, 0x72, 0x22, 0xcc, 0x02, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x39, 0x0a, 0x0d, 0x76, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x62, 0x69,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x68, 0x61, 0x73, 0x68, 0x42, 0x69,
+	0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x48, 0x0a, 0x12, 0x73,
+	0x74, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x11, 0x73, 0x74, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x7a, 0x65, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x31, 0x0a, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0xf8, 0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c,
+	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
+	0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x73,
+	0x74, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x73, 0x74, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x7a, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x75,
+	0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65,
+	0x61, 0x74, 0x68, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x64, 0x65, 0x61, 0x74,
+	0x68, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65,
+	0x74, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x32, 0x92, 0x02,
+	0x0a, 0x0c, 0x43, 0x68, 0x6f, 0x72, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x34,
+	0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x12,
+	0x10, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65,
+	0x71, 0x1a, 0x11, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x33, 0x0a, 0x10, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x73, 0x74, 0x50,
+	0x72, 0x65, 0x63, 0x65, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f,
+	0x72, 0x64, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x0d, 0x2e, 0x63, 0x6f, 0x6e,
+	0x63, 0x6f, 0x72, 0x64, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x12, 0x30, 0x0a, 0x07, 0x47, 0x65, 0x74,
+	0x52, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0d, 0x2e, 0x63,
+	0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a, 0x06, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0f, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x32,
+	0x0a, 0x05, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x12, 0x11, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72,
+	0x64, 0x2e, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x32, 0xf7, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x12, 0x30, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0d, 0x2e, 0x63, 0x6f, 0x6e, 0x63, 0x6f, 0x72, 0x64,
+	0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x32, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0e, 0x2e, 0x63, 0x6f, 0x6e, 0x63,
+	0x6f, 0x72, 0x64, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x40, 0x0a, 0x0e, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x53, 0x74, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3f, 0x0a, 0x0d, 0x46,
+	0x69, 0x78, 0x41, 0x6c, 0x6c, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x07, 0x5a, 0x05,
+	0x2e, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -277,29 +1078,67 @@ func file_proto_concord_proto_rawDescGZIP() []byte {
 	return file_proto_concord_proto_rawDescData
 }
 
-var file_proto_concord_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_concord_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_concord_proto_goTypes = []any{
-	(*FindReq)(nil),       // 0: concord.FindReq
-	(*FindResp)(nil),      // 1: concord.FindResp
-	(*Server)(nil),        // 2: concord.Server
-	(*Ring)(nil),          // 3: concord.Ring
-	(*emptypb.Empty)(nil), // 4: google.protobuf.Empty
+	(*FindReq)(nil),             // 0: concord.FindReq
+	(*FindResp)(nil),            // 1: concord.FindResp
+	(*Hop)(nil),                 // 2: concord.Hop
+	(*Step)(nil),                // 3: concord.Step
+	(*Server)(nil),              // 4: concord.Server
+	(*Ring)(nil),                // 5: concord.Ring
+	(*LeaveReq)(nil),            // 6: concord.LeaveReq
+	(*Range)(nil),               // 7: concord.Range
+	(*Finger)(nil),              // 8: concord.Finger
+	(*VirtualNode)(nil),         // 9: concord.VirtualNode
+	(*Info)(nil),                // 10: concord.Info
+	(*Stats)(nil),               // 11: concord.Stats
+	(*durationpb.Duration)(nil), // 12: google.protobuf.Duration
+	(*emptypb.Empty)(nil),       // 13: google.protobuf.Empty
 }
 var file_proto_concord_proto_depIdxs = []int32{
-	2, // 0: concord.FindResp.server:type_name -> concord.Server
-	2, // 1: concord.Ring.predecessor:type_name -> concord.Server
-	2, // 2: concord.Ring.successors:type_name -> concord.Server
-	0, // 3: concord.ChordService.FindSuccessor:input_type -> concord.FindReq
-	4, // 4: concord.ChordService.GetRing:input_type -> google.protobuf.Empty
-	2, // 5: concord.ChordService.Notify:input_type -> concord.Server
-	1, // 6: concord.ChordService.FindSuccessor:output_type -> concord.FindResp
-	3, // 7: concord.ChordService.GetRing:output_type -> concord.Ring
-	4, // 8: concord.ChordService.Notify:output_type -> google.protobuf.Empty
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	4,  // 0: concord.FindResp.server:type_name -> concord.Server
+	2,  // 1: concord.FindResp.hops:type_name -> concord.Hop
+	4,  // 2: concord.Hop.server:type_name -> concord.Server
+	12, // 3: concord.Hop.latency:type_name -> google.protobuf.Duration
+	4,  // 4: concord.Step.server:type_name -> concord.Server
+	4,  // 5: concord.Step.successors:type_name -> concord.Server
+	4,  // 6: concord.Ring.predecessor:type_name -> concord.Server
+	4,  // 7: concord.Ring.successors:type_name -> concord.Server
+	4,  // 8: concord.LeaveReq.server:type_name -> concord.Server
+	4,  // 9: concord.LeaveReq.predecessor:type_name -> concord.Server
+	4,  // 10: concord.LeaveReq.successors:type_name -> concord.Server
+	4,  // 11: concord.Finger.node:type_name -> concord.Server
+	4,  // 12: concord.VirtualNode.self:type_name -> concord.Server
+	7,  // 13: concord.VirtualNode.range:type_name -> concord.Range
+	4,  // 14: concord.VirtualNode.predecessor:type_name -> concord.Server
+	4,  // 15: concord.VirtualNode.successors:type_name -> concord.Server
+	8,  // 16: concord.VirtualNode.fingers:type_name -> concord.Finger
+	9,  // 17: concord.Info.virtual_nodes:type_name -> concord.VirtualNode
+	12, // 18: concord.Info.stabilize_interval:type_name -> google.protobuf.Duration
+	12, // 19: concord.Info.uptime:type_name -> google.protobuf.Duration
+	0,  // 20: concord.ChordService.FindSuccessor:input_type -> concord.FindReq
+	0,  // 21: concord.ChordService.ClosestPreceding:input_type -> concord.FindReq
+	13, // 22: concord.ChordService.GetRing:input_type -> google.protobuf.Empty
+	4,  // 23: concord.ChordService.Notify:input_type -> concord.Server
+	6,  // 24: concord.ChordService.Leave:input_type -> concord.LeaveReq
+	13, // 25: concord.ConcordAdmin.GetInfo:input_type -> google.protobuf.Empty
+	13, // 26: concord.ConcordAdmin.GetStats:input_type -> google.protobuf.Empty
+	13, // 27: concord.ConcordAdmin.ForceStabilize:input_type -> google.protobuf.Empty
+	13, // 28: concord.ConcordAdmin.FixAllFingers:input_type -> google.protobuf.Empty
+	1,  // 29: concord.ChordService.FindSuccessor:output_type -> concord.FindResp
+	3,  // 30: concord.ChordService.ClosestPreceding:output_type -> concord.Step
+	5,  // 31: concord.ChordService.GetRing:output_type -> concord.Ring
+	13, // 32: concord.ChordService.Notify:output_type -> google.protobuf.Empty
+	13, // 33: concord.ChordService.Leave:output_type -> google.protobuf.Empty
+	10, // 34: concord.ConcordAdmin.GetInfo:output_type -> concord.Info
+	11, // 35: concord.ConcordAdmin.GetStats:output_type -> concord.Stats
+	13, // 36: concord.ConcordAdmin.ForceStabilize:output_type -> google.protobuf.Empty
+	13, // 37: concord.ConcordAdmin.FixAllFingers:output_type -> google.protobuf.Empty
+	29, // [29:38] is the sub-list for method output_type
+	20, // [20:29] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_proto_concord_proto_init() }
@@ -308,16 +1147,19 @@ func file_proto_concord_proto_init() {
 		return
 	}
 	file_proto_concord_proto_msgTypes[1].OneofWrappers = []any{}
-	file_proto_concord_proto_msgTypes[3].OneofWrappers = []any{}
+	file_proto_concord_proto_msgTypes[5].OneofWrappers = []any{}
+	file_proto_concord_proto_msgTypes[6].OneofWrappers = []any{}
+	file_proto_concord_proto_msgTypes[8].OneofWrappers = []any{}
+	file_proto_concord_proto_msgTypes[9].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_concord_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   12,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_concord_proto_goTypes,
 		DependencyIndexes: file_proto_concord_proto_depIdxs,
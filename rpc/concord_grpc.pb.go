@@ -20,9 +20,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChordService_FindSuccessor_FullMethodName = "/concord.ChordService/FindSuccessor"
-	ChordService_GetRing_FullMethodName       = "/concord.ChordService/GetRing"
-	ChordService_Notify_FullMethodName        = "/concord.ChordService/Notify"
+	ChordService_FindSuccessor_FullMethodName    = "/concord.ChordService/FindSuccessor"
+	ChordService_ClosestPreceding_FullMethodName = "/concord.ChordService/ClosestPreceding"
+	ChordService_GetRing_FullMethodName          = "/concord.ChordService/GetRing"
+	ChordService_Notify_FullMethodName           = "/concord.ChordService/Notify"
+	ChordService_Leave_FullMethodName            = "/concord.ChordService/Leave"
 )
 
 // ChordServiceClient is the client API for ChordService service.
@@ -30,8 +32,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ChordServiceClient interface {
 	FindSuccessor(ctx context.Context, in *FindReq, opts ...grpc.CallOption) (*FindResp, error)
+	ClosestPreceding(ctx context.Context, in *FindReq, opts ...grpc.CallOption) (*Step, error)
 	GetRing(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Ring, error)
 	Notify(ctx context.Context, in *Server, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Leave(ctx context.Context, in *LeaveReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type chordServiceClient struct {
@@ -52,6 +56,16 @@ func (c *chordServiceClient) FindSuccessor(ctx context.Context, in *FindReq, opt
 	return out, nil
 }
 
+func (c *chordServiceClient) ClosestPreceding(ctx context.Context, in *FindReq, opts ...grpc.CallOption) (*Step, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Step)
+	err := c.cc.Invoke(ctx, ChordService_ClosestPreceding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chordServiceClient) GetRing(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Ring, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Ring)
@@ -72,13 +86,25 @@ func (c *chordServiceClient) Notify(ctx context.Context, in *Server, opts ...grp
 	return out, nil
 }
 
+func (c *chordServiceClient) Leave(ctx context.Context, in *LeaveReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ChordService_Leave_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChordServiceServer is the server API for ChordService service.
 // All implementations must embed UnimplementedChordServiceServer
 // for forward compatibility.
 type ChordServiceServer interface {
 	FindSuccessor(context.Context, *FindReq) (*FindResp, error)
+	ClosestPreceding(context.Context, *FindReq) (*Step, error)
 	GetRing(context.Context, *emptypb.Empty) (*Ring, error)
 	Notify(context.Context, *Server) (*emptypb.Empty, error)
+	Leave(context.Context, *LeaveReq) (*emptypb.Empty, error)
 	mustEmbedUnimplementedChordServiceServer()
 }
 
@@ -92,12 +118,18 @@ type UnimplementedChordServiceServer struct{}
 func (UnimplementedChordServiceServer) FindSuccessor(context.Context, *FindReq) (*FindResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindSuccessor not implemented")
 }
+func (UnimplementedChordServiceServer) ClosestPreceding(context.Context, *FindReq) (*Step, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosestPreceding not implemented")
+}
 func (UnimplementedChordServiceServer) GetRing(context.Context, *emptypb.Empty) (*Ring, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRing not implemented")
 }
 func (UnimplementedChordServiceServer) Notify(context.Context, *Server) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
 }
+func (UnimplementedChordServiceServer) Leave(context.Context, *LeaveReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
+}
 func (UnimplementedChordServiceServer) mustEmbedUnimplementedChordServiceServer() {}
 func (UnimplementedChordServiceServer) testEmbeddedByValue()                      {}
 
@@ -137,6 +169,24 @@ func _ChordService_FindSuccessor_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChordService_ClosestPreceding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServiceServer).ClosestPreceding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChordService_ClosestPreceding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServiceServer).ClosestPreceding(ctx, req.(*FindReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChordService_GetRing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -173,6 +223,24 @@ func _ChordService_Notify_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChordService_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChordServiceServer).Leave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChordService_Leave_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChordServiceServer).Leave(ctx, req.(*LeaveReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChordService_ServiceDesc is the grpc.ServiceDesc for ChordService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -184,6 +252,10 @@ var ChordService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindSuccessor",
 			Handler:    _ChordService_FindSuccessor_Handler,
 		},
+		{
+			MethodName: "ClosestPreceding",
+			Handler:    _ChordService_ClosestPreceding_Handler,
+		},
 		{
 			MethodName: "GetRing",
 			Handler:    _ChordService_GetRing_Handler,
@@ -192,6 +264,226 @@ var ChordService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Notify",
 			Handler:    _ChordService_Notify_Handler,
 		},
+		{
+			MethodName: "Leave",
+			Handler:    _ChordService_Leave_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/concord.proto",
+}
+
+const (
+	ConcordAdmin_GetInfo_FullMethodName        = "/concord.ConcordAdmin/GetInfo"
+	ConcordAdmin_GetStats_FullMethodName       = "/concord.ConcordAdmin/GetStats"
+	ConcordAdmin_ForceStabilize_FullMethodName = "/concord.ConcordAdmin/ForceStabilize"
+	ConcordAdmin_FixAllFingers_FullMethodName  = "/concord.ConcordAdmin/FixAllFingers"
+)
+
+// ConcordAdminClient is the client API for ConcordAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConcordAdminClient interface {
+	GetInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Info, error)
+	GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Stats, error)
+	ForceStabilize(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	FixAllFingers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type concordAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConcordAdminClient(cc grpc.ClientConnInterface) ConcordAdminClient {
+	return &concordAdminClient{cc}
+}
+
+func (c *concordAdminClient) GetInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Info, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Info)
+	err := c.cc.Invoke(ctx, ConcordAdmin_GetInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *concordAdminClient) GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, ConcordAdmin_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *concordAdminClient) ForceStabilize(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ConcordAdmin_ForceStabilize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *concordAdminClient) FixAllFingers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ConcordAdmin_FixAllFingers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConcordAdminServer is the server API for ConcordAdmin service.
+// All implementations must embed UnimplementedConcordAdminServer
+// for forward compatibility.
+type ConcordAdminServer interface {
+	GetInfo(context.Context, *emptypb.Empty) (*Info, error)
+	GetStats(context.Context, *emptypb.Empty) (*Stats, error)
+	ForceStabilize(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	FixAllFingers(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	mustEmbedUnimplementedConcordAdminServer()
+}
+
+// UnimplementedConcordAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConcordAdminServer struct{}
+
+func (UnimplementedConcordAdminServer) GetInfo(context.Context, *emptypb.Empty) (*Info, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedConcordAdminServer) GetStats(context.Context, *emptypb.Empty) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedConcordAdminServer) ForceStabilize(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceStabilize not implemented")
+}
+func (UnimplementedConcordAdminServer) FixAllFingers(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FixAllFingers not implemented")
+}
+func (UnimplementedConcordAdminServer) mustEmbedUnimplementedConcordAdminServer() {}
+func (UnimplementedConcordAdminServer) testEmbeddedByValue()                      {}
+
+// UnsafeConcordAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConcordAdminServer will
+// result in compilation errors.
+type UnsafeConcordAdminServer interface {
+	mustEmbedUnimplementedConcordAdminServer()
+}
+
+func RegisterConcordAdminServer(s grpc.ServiceRegistrar, srv ConcordAdminServer) {
+	// If the following call pancis, it indicates UnimplementedConcordAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ConcordAdmin_ServiceDesc, srv)
+}
+
+func _ConcordAdmin_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConcordAdminServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConcordAdmin_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConcordAdminServer).GetInfo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConcordAdmin_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConcordAdminServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConcordAdmin_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConcordAdminServer).GetStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConcordAdmin_ForceStabilize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConcordAdminServer).ForceStabilize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConcordAdmin_ForceStabilize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConcordAdminServer).ForceStabilize(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConcordAdmin_FixAllFingers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConcordAdminServer).FixAllFingers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConcordAdmin_FixAllFingers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConcordAdminServer).FixAllFingers(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConcordAdmin_ServiceDesc is the grpc.ServiceDesc for ConcordAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConcordAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "concord.ConcordAdmin",
+	HandlerType: (*ConcordAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetInfo",
+			Handler:    _ConcordAdmin_GetInfo_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _ConcordAdmin_GetStats_Handler,
+		},
+		{
+			MethodName: "ForceStabilize",
+			Handler:    _ConcordAdmin_ForceStabilize_Handler,
+		},
+		{
+			MethodName: "FixAllFingers",
+			Handler:    _ConcordAdmin_FixAllFingers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/concord.proto",
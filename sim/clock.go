@@ -0,0 +1,216 @@
+package sim
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ollelogdahl/concord"
+)
+
+// The time a simulation starts at.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// A virtual clock, implementing concord.Clock. Scheduled calls run one at a
+// time, in the goroutine advancing the clock, ordered by time and then by the
+// order they were scheduled in.
+//
+// Time seen by a call starts at the time it was scheduled for, and moves ahead
+// as the call waits on the network. Calls scheduled at the same time therefore
+// run one after another, but each sees its own time pass as if they ran in
+// parallel.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    uint64
+	events events
+}
+
+var _ concord.Clock = (*Clock)(nil)
+
+// Creates a clock at Epoch.
+func NewClock() *Clock {
+	return &Clock{now: Epoch}
+}
+
+// Returns the current virtual time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Schedules f to run every d of virtual time. Ticks due while f runs, as seen
+// by f, are dropped.
+func (c *Clock) NewTicker(d time.Duration, f func()) concord.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &ticker{clock: c, period: d, f: f}
+	t.next = c.schedule(c.now.Add(d), t.tick)
+	return t
+}
+
+// Schedules f to run after d of virtual time.
+func (c *Clock) AfterFunc(d time.Duration, f func()) concord.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.schedule(c.now.Add(d), f)
+}
+
+// must be called with the lock held.
+func (c *Clock) schedule(at time.Time, f func()) *event {
+	c.seq++
+	e := &event{clock: c, at: at, seq: c.seq, f: f}
+	heap.Push(&c.events, e)
+	return e
+}
+
+// Runs all calls due within d of virtual time, then returns. Returns early
+// only if ctx is already done.
+func (c *Clock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.Advance(d)
+	return ctx.Err()
+}
+
+// Advances the clock by d, running every call due until then.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	until := c.now.Add(d)
+	c.mu.Unlock()
+
+	c.runUntil(until)
+}
+
+// runs calls due at or before until, in order, then moves the clock to until.
+func (c *Clock) runUntil(until time.Time) {
+	for {
+		c.mu.Lock()
+		if len(c.events) == 0 || c.events[0].at.After(until) {
+			if c.now.Before(until) {
+				c.now = until
+			}
+			c.mu.Unlock()
+			return
+		}
+		e := heap.Pop(&c.events).(*event)
+		c.now = e.at
+		c.mu.Unlock()
+
+		e.f()
+	}
+}
+
+// moves the time seen by the running call ahead by d, without running other
+// calls; as when it waits on the network.
+func (c *Clock) elapse(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+type ticker struct {
+	clock   *Clock
+	period  time.Duration
+	f       func()
+	next    *event
+	stopped bool
+}
+
+func (t *ticker) tick() {
+	c := t.clock
+	c.mu.Lock()
+	at := t.next.at
+	c.mu.Unlock()
+
+	t.f()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.stopped {
+		return
+	}
+	for !at.After(c.now) {
+		at = at.Add(t.period)
+	}
+	t.next = c.schedule(at, t.tick)
+}
+
+func (t *ticker) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t.stopped = true
+	if t.next.index >= 0 {
+		heap.Remove(&c.events, t.next.index)
+	}
+}
+
+// a call scheduled on a Clock.
+type event struct {
+	clock *Clock
+	at    time.Time
+	seq   uint64
+	f     func()
+	index int
+}
+
+func (e *event) Stop() bool {
+	c := e.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e.index < 0 {
+		return false
+	}
+	heap.Remove(&c.events, e.index)
+	return true
+}
+
+// a min-heap of events, by time and then by sequence.
+type events []*event
+
+func (h events) Len() int {
+	return len(h)
+}
+
+func (h events) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h events) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *events) Push(x any) {
+	e := x.(*event)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *events) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
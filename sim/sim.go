@@ -0,0 +1,278 @@
+// sim runs whole concord rings in one process, on a virtual clock and a
+// simulated network, so that a run is reproducible from its seed.
+//
+// Every node is driven by the clock of the simulation: its stabilization,
+// join retries and background notifications are scheduled on it, and run one
+// at a time in the goroutine calling Run. Requests between nodes are delivered
+// through a concord.MemoryNetwork, with latency, loss and partitions applied.
+//
+// A simulation is only deterministic while nothing else runs concurrently: its
+// nodes must only be used from the goroutine driving it, and context deadlines,
+// which follow the wall clock, are not simulated.
+//
+// Requests are handled at once by the receiving node; latency only moves the
+// time seen by the sender ahead. Calls that send several requests thus see the
+// time they would take, while the ring observes each request as soon as it is
+// sent.
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ollelogdahl/concord"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Config struct {
+	Seed uint64
+
+	// The round-trip time of each request is drawn uniformly between the two.
+	MinLatency time.Duration
+	MaxLatency time.Duration
+
+	// The probability of a request being lost.
+	Loss float64
+	// How long a sender waits for a request that is lost, or sent across a
+	// partition, before failing.
+	Timeout time.Duration
+}
+
+// A simulated ring.
+type Sim struct {
+	config  Config
+	clock   *Clock
+	network *concord.MemoryNetwork
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	nodes     []*concord.Concord
+	crashed   map[string]bool
+	partition map[string]int
+}
+
+// Creates an empty simulation.
+func New(config Config) *Sim {
+	if config.MaxLatency < config.MinLatency {
+		config.MaxLatency = config.MinLatency
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	s := &Sim{
+		config:    config,
+		clock:     NewClock(),
+		network:   concord.NewMemoryNetwork(),
+		rng:       rand.New(rand.NewPCG(config.Seed, config.Seed)),
+		crashed:   make(map[string]bool),
+		partition: make(map[string]int),
+	}
+	s.network.SetDeliveryHook(s.deliver)
+
+	return s
+}
+
+// Returns the clock driving the simulation.
+func (s *Sim) Clock() *Clock {
+	return s.clock
+}
+
+// Returns the current virtual time.
+func (s *Sim) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Creates and starts a node on the simulated network. The address of the node
+// defaults to its name; its transport, clock and randomness are provided by the
+// simulation, and its logs are discarded unless a LogHandler is set.
+func (s *Sim) Spawn(config concord.Config) (*concord.Concord, error) {
+	if config.BindAddr == "" {
+		config.BindAddr = config.Name
+	}
+	if config.AdvAddr == "" {
+		config.AdvAddr = config.BindAddr
+	}
+	if config.LogHandler == nil {
+		config.LogHandler = slog.DiscardHandler
+	}
+
+	s.mu.Lock()
+	config.RandSource = rand.NewPCG(s.rng.Uint64(), s.rng.Uint64())
+	s.mu.Unlock()
+
+	config.Transport = s.network.Transport()
+	config.Clock = s.clock
+
+	node := concord.New(config)
+	if err := node.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", config.Name, err)
+	}
+
+	s.mu.Lock()
+	s.nodes = append(s.nodes, node)
+	s.mu.Unlock()
+
+	return node, nil
+}
+
+// Crashes a node; it stops at once, without leaving the ring.
+func (s *Sim) Crash(node *concord.Concord) {
+	s.mu.Lock()
+	s.crashed[node.Address()] = true
+	s.mu.Unlock()
+
+	node.Stop()
+}
+
+// Returns the nodes that have not crashed, in the order they were spawned.
+func (s *Sim) Nodes() []*concord.Concord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var nodes []*concord.Concord
+	for _, n := range s.nodes {
+		if !s.crashed[n.Address()] {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Splits the network; nodes can only reach nodes in the same group. Nodes in
+// no group form a group of their own. Replaces any previous partition.
+func (s *Sim) Partition(groups ...[]*concord.Concord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.partition = make(map[string]int)
+	for i, g := range groups {
+		for _, n := range g {
+			s.partition[n.Address()] = i + 1
+		}
+	}
+}
+
+// Removes any partition of the network.
+func (s *Sim) Heal() {
+	s.Partition()
+}
+
+// Schedules f to run after d of virtual time.
+func (s *Sim) Schedule(d time.Duration, f func()) {
+	s.clock.AfterFunc(d, f)
+}
+
+// Runs the simulation for d of virtual time.
+func (s *Sim) Run(d time.Duration) {
+	s.clock.Advance(d)
+}
+
+// Runs the simulation until cond holds, checking it every interval, for at most
+// limit of virtual time. Returns the time it took, and whether cond held.
+func (s *Sim) RunUntil(cond func() bool, interval, limit time.Duration) (time.Duration, bool) {
+	start := s.Now()
+	for {
+		if cond() {
+			return s.Now().Sub(start), true
+		}
+		if s.Now().Sub(start) >= limit {
+			return s.Now().Sub(start), false
+		}
+		s.Run(interval)
+	}
+}
+
+// Runs the simulation until the ring is consistent, as reported by Consistent,
+// for at most limit of virtual time. Returns the time it took, and the last
+// inconsistency if it did not settle.
+func (s *Sim) Settle(limit time.Duration) (time.Duration, error) {
+	var err error
+	took, _ := s.RunUntil(func() bool {
+		err = s.Consistent()
+		return err == nil
+	}, 100*time.Millisecond, limit)
+	return took, err
+}
+
+// Returns an error describing the first inconsistency of the ring formed by the
+// nodes that have not crashed; nil if each one has the next node on the ring as
+// its successor and the previous one as its predecessor, and their ranges
+// cover the whole ring.
+func (s *Sim) Consistent() error {
+	nodes := s.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var ring []concord.Server
+	for _, n := range nodes {
+		ring = append(ring, n.VirtualNodes()...)
+	}
+	slices.SortFunc(ring, func(a, b concord.Server) int {
+		return a.Id.Cmp(b.Id)
+	})
+
+	index := func(id concord.ID) int {
+		i, _ := slices.BinarySearchFunc(ring, id, func(s concord.Server, id concord.ID) int {
+			return s.Id.Cmp(id)
+		})
+		return i
+	}
+
+	for _, n := range nodes {
+		i := index(n.Id())
+		succ := ring[(i+1)%len(ring)]
+		pred := ring[(i+len(ring)-1)%len(ring)]
+
+		successors := n.Successors()
+		if len(successors) == 0 || successors[0].Id != succ.Id {
+			return fmt.Errorf("%s (%s) does not have %s (%s) as successor", n.Name(), n.Id(), succ.Name, succ.Id)
+		}
+		p, ok := n.Predecessor()
+		if !ok || p.Id != pred.Id {
+			return fmt.Errorf("%s (%s) does not have %s (%s) as predecessor", n.Name(), n.Id(), pred.Name, pred.Id)
+		}
+	}
+
+	var ranges []concord.Range
+	for _, n := range nodes {
+		ranges = append(ranges, n.Ranges()...)
+	}
+	slices.SortFunc(ranges, func(a, b concord.Range) int {
+		return a.End.Cmp(b.End)
+	})
+	for i, r := range ranges {
+		prev := ranges[(i+len(ranges)-1)%len(ranges)]
+		if r.Start != prev.End {
+			return fmt.Errorf("range (%s, %s] does not start where (%s, %s] ends", r.Start, r.End, prev.Start, prev.End)
+		}
+	}
+
+	return nil
+}
+
+// applies the conditions of the network to a request.
+func (s *Sim) deliver(from, to, method string) error {
+	s.mu.Lock()
+	lost := s.partition[from] != s.partition[to] || (s.config.Loss > 0 && s.rng.Float64() < s.config.Loss)
+	latency := s.config.MinLatency
+	if spread := s.config.MaxLatency - s.config.MinLatency; spread > 0 {
+		latency += time.Duration(s.rng.Int64N(int64(spread)))
+	}
+	s.mu.Unlock()
+
+	if lost {
+		s.clock.elapse(s.config.Timeout)
+		return status.Errorf(codes.DeadlineExceeded, "request from %s to %s lost", from, to)
+	}
+
+	s.clock.elapse(latency)
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 
 type Sample struct {
 	DurationMs float64 `json:"duration_ms"`
+	Hops       int     `json:"hops,omitempty"`
 }
 
 type BenchmarkRun struct {
@@ -32,7 +33,7 @@ var (
 	outputFile = "bench_results.json"
 )
 
-func recordSample(name string, params map[string]int, durationMs float64) {
+func recordSample(name string, params map[string]int, sample Sample) {
 	mu.Lock()
 	defer mu.Unlock()
 	var run *BenchmarkRun
@@ -51,7 +52,7 @@ func recordSample(name string, params map[string]int, durationMs float64) {
 		})
 		run = &results[len(results)-1]
 	}
-	run.Samples = append(run.Samples, Sample{DurationMs: durationMs})
+	run.Samples = append(run.Samples, sample)
 }
 
 func saveResults() {
@@ -109,7 +110,7 @@ func isRingStable(nodes []*concord.Concord) bool {
 			return false
 		}
 	}
-	visited := make(map[uint64]bool)
+	visited := make(map[concord.ID]bool)
 	current := nodes[0].Id()
 	for i := 0; i < len(nodes)*2; i++ {
 		if visited[current] {
@@ -166,12 +167,12 @@ func reportLookup() {
 		for i := 0; i < iterations; i++ {
 			key := keyList[i%len(keyList)]
 			start := time.Now()
-			_, err := nodes[0].Lookup(key)
+			_, hops, err := nodes[0].LookupTrace(context.Background(), key)
 			if err != nil {
 				continue
 			}
 			d := time.Since(start).Seconds() * 1000
-			recordSample(name, params, d)
+			recordSample(name, params, Sample{DurationMs: d, Hops: len(hops)})
 		}
 		saveResults()
 		cleanup(nodes)
@@ -199,7 +200,7 @@ func reportJoin() {
 				log.Fatalf("join err: %v", err)
 			}
 			d := float64(time.Since(start).Milliseconds())
-			recordSample(name, params, d)
+			recordSample(name, params, Sample{DurationMs: d})
 			joining.Stop()
 			cleanup(nodes)
 		}
@@ -224,12 +225,12 @@ func reportConcurrentLookups() {
 				for k := 0; k < opsPerRoutine; k++ {
 					key := []byte(fmt.Sprintf("key-%d-%d", rid, k))
 					start := time.Now()
-					_, err := node.Lookup(key)
+					_, hops, err := node.LookupTrace(context.Background(), key)
 					if err != nil {
 						continue
 					}
 					d := time.Since(start).Seconds() * 1000
-					recordSample(name, params, d)
+					recordSample(name, params, Sample{DurationMs: d, Hops: len(hops)})
 				}
 			}(j)
 		}
@@ -8,15 +8,19 @@ import (
 	"time"
 
 	"github.com/ollelogdahl/concord"
+	"github.com/ollelogdahl/concord/sim"
 	"github.com/ollelogdahl/concord/test/fuzz/fz"
+	"github.com/ollelogdahl/concord/test/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 type State struct {
-	Nodes map[string]*concord.Concord
-	Addrs map[string]string
+	Nodes   map[string]*concord.Concord
+	Addrs   map[string]string
+	Network *concord.MemoryNetwork
+	Clock   *sim.Clock
 
-	portIncrementor uint
+	nodeIncrementor uint
 }
 
 type spawnP struct {
@@ -61,7 +65,7 @@ func genSpawn(s *State) []spawnP {
 
 	for name := range s.Nodes {
 		tasks = append(tasks, spawnP{
-			Name:   fmt.Sprintf("cord%d", s.portIncrementor),
+			Name:   fmt.Sprintf("cord%d", s.nodeIncrementor),
 			ToJoin: name,
 		})
 	}
@@ -96,29 +100,28 @@ func genKill(s *State) []killP {
 	return tasks
 }
 
-func (s *State) nextAddrs() (string, string) {
-	baddr := fmt.Sprintf(":%d", 10000+s.portIncrementor)
-	addr := fmt.Sprintf("localhost%s", baddr)
-
-	return baddr, addr
+func (s *State) nextAddr() string {
+	return fmt.Sprintf("mem-%d", s.nodeIncrementor)
 }
 
 func (s *State) add(name string, addr string, instance *concord.Concord) {
 	s.Nodes[name] = instance
 	s.Addrs[name] = addr
-	s.portIncrementor++
+	s.nodeIncrementor++
 }
 
 func doSpawn(s *State, p spawnP) {
 	name := p.Name
-	baddr, addr := s.nextAddrs()
+	addr := s.nextAddr()
 
 	cfg := concord.Config{
 		Name:              name,
-		BindAddr:          baddr,
+		BindAddr:          addr,
 		AdvAddr:           addr,
 		StabilizeInterval: STABILIZE_INTERVAL,
 		SuccessorCount:    MAX_SIMULTANEOUS_KILLS + 1,
+		Transport:         s.Network.Transport(),
+		Clock:             s.Clock,
 	}
 
 	instance := concord.New(cfg)
@@ -138,70 +141,83 @@ func doKill(s *State, p killP) {
 	}
 }
 
+// advances the virtual clock of the nodes until the ring is consistent, for at
+// most a minute of virtual time.
 func invEvConsistentRingAndCoverage(t assert.TestingT, s *State) {
-	assert.EventuallyWithT(t, func(ct *assert.CollectT) {
-		// ensure that:
-		// - \forall n \in s.Nodes n.Successor \in s.Nodes
-		// - following successors forms a correct ring
-		// - \forall n \in s.Nodes nodes[n.Successor].Predecessor = n
-		if len(s.Nodes) == 0 {
-			assert.Fail(ct, "No nodes in state")
+	const tick = 100 * time.Millisecond
+
+	for waited := time.Duration(0); waited < 60*time.Second; waited += tick {
+		var rec testutil.Recorder
+		checkConsistentRing(&rec, s)
+		if !rec.Failed {
 			return
 		}
+		s.Clock.Advance(tick)
+	}
+	checkConsistentRing(t, s)
+}
 
-		for name, node := range s.Nodes {
-			// Check 1: Successor exists in cluster
-			successors := node.Successors()
-			if len(successors) == 0 {
-				assert.Fail(ct, "Node %s has no successors", name)
-				return
-			}
+func checkConsistentRing(ct assert.TestingT, s *State) {
+	// ensure that:
+	// - \forall n \in s.Nodes n.Successor \in s.Nodes
+	// - following successors forms a correct ring
+	// - \forall n \in s.Nodes nodes[n.Successor].Predecessor = n
+	if len(s.Nodes) == 0 {
+		assert.Fail(ct, "No nodes in state")
+		return
+	}
 
-			if _, exists := s.Nodes[successors[0].Name]; !exists {
-				assert.Fail(ct, "Node %s has successor %s not in cluster", name, successors[0].Name)
-				return
-			}
+	for name, node := range s.Nodes {
+		// Check 1: Successor exists in cluster
+		successors := node.Successors()
+		if len(successors) == 0 {
+			assert.Fail(ct, "Node %s has no successors", name)
+			return
+		}
 
-			// Check 2: Predecessor exists and is symmetric
-			pred, ok := node.Predecessor()
-			assert.True(ct, ok, "Node %s has no predecessor", name)
+		if _, exists := s.Nodes[successors[0].Name]; !exists {
+			assert.Fail(ct, "Node %s has successor %s not in cluster", name, successors[0].Name)
+			return
+		}
 
-			predNode, exists := s.Nodes[pred.Name]
-			if !exists {
-				assert.Fail(ct, "Node %s has predecessor %s not in cluster", name, pred.Name)
-				return
-			}
+		// Check 2: Predecessor exists and is symmetric
+		pred, ok := node.Predecessor()
+		assert.True(ct, ok, "Node %s has no predecessor", name)
 
-			predSuccessors := predNode.Successors()
-			if len(predSuccessors) == 0 || predSuccessors[0].Name != name {
-				assert.Fail(ct, "Predecessor link broken: %s -> %s -/-> %s", name, pred.Name, name)
-				return
-			}
+		predNode, exists := s.Nodes[pred.Name]
+		if !exists {
+			assert.Fail(ct, "Node %s has predecessor %s not in cluster", name, pred.Name)
+			return
 		}
 
-		// Check 3: Ring traversal visits all nodes
-		var startNode *concord.Concord
-		for _, node := range s.Nodes {
-			startNode = node
-			break
+		predSuccessors := predNode.Successors()
+		if len(predSuccessors) == 0 || predSuccessors[0].Name != name {
+			assert.Fail(ct, "Predecessor link broken: %s -> %s -/-> %s", name, pred.Name, name)
+			return
 		}
+	}
 
-		visited := make(map[string]bool)
-		current := startNode
+	// Check 3: Ring traversal visits all nodes
+	var startNode *concord.Concord
+	for _, node := range s.Nodes {
+		startNode = node
+		break
+	}
 
-		for len(visited) < len(s.Nodes) {
-			name := current.Name()
-			if visited[name] {
-				assert.Fail(ct, "inconsistent", "Ring has cycle before visiting all nodes. expected %+v, visited %+v", s.Nodes, visited)
-				return
-			}
-			visited[name] = true
+	visited := make(map[string]bool)
+	current := startNode
 
-			nextName := current.Successors()[0].Name
-			current = s.Nodes[nextName]
+	for len(visited) < len(s.Nodes) {
+		name := current.Name()
+		if visited[name] {
+			assert.Fail(ct, "inconsistent", "Ring has cycle before visiting all nodes. expected %+v, visited %+v", s.Nodes, visited)
+			return
 		}
+		visited[name] = true
 
-	}, 60*time.Second, 100*time.Millisecond)
+		nextName := current.Successors()[0].Name
+		current = s.Nodes[nextName]
+	}
 }
 
 const MAX_SIMULATED_NODES = 10
@@ -228,20 +244,24 @@ func main() {
 	initialState := State{
 		Nodes:           make(map[string]*concord.Concord),
 		Addrs:           make(map[string]string),
-		portIncrementor: 0,
+		Network:         concord.NewMemoryNetwork(),
+		Clock:           sim.NewClock(),
+		nodeIncrementor: 0,
 	}
 
 	// add the initial node
 	{
 		name := "cord0"
-		baddr, addr := initialState.nextAddrs()
+		addr := initialState.nextAddr()
 
 		cfg := concord.Config{
 			Name:              name,
-			BindAddr:          baddr,
+			BindAddr:          addr,
 			AdvAddr:           addr,
 			StabilizeInterval: STABILIZE_INTERVAL,
 			SuccessorCount:    MAX_SIMULTANEOUS_KILLS - 1,
+			Transport:         initialState.Network.Transport(),
+			Clock:             initialState.Clock,
 		}
 
 		instance := concord.New(cfg)
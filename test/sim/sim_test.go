@@ -0,0 +1,237 @@
+package sim_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ollelogdahl/concord"
+	"github.com/ollelogdahl/concord/sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spawns n nodes, joining one every interval through the first.
+func spawnRing(t *testing.T, s *sim.Sim, n int, interval time.Duration, configure func(*concord.Config)) []*concord.Concord {
+	nodes := make([]*concord.Concord, n)
+	for i := range n {
+		config := concord.Config{Name: fmt.Sprintf("node-%d", i)}
+		if configure != nil {
+			configure(&config)
+		}
+
+		node, err := s.Spawn(config)
+		require.NoError(t, err)
+		nodes[i] = node
+
+		if i == 0 {
+			require.NoError(t, node.Create())
+			continue
+		}
+		require.NoError(t, node.Join(context.Background(), nodes[0].Address()))
+		s.Run(interval)
+	}
+	return nodes
+}
+
+func TestSimulatedRingConverges(t *testing.T) {
+	s := sim.New(sim.Config{
+		Seed:       1,
+		MinLatency: time.Millisecond,
+		MaxLatency: 20 * time.Millisecond,
+	})
+
+	nodes := spawnRing(t, s, 1000, time.Second, nil)
+
+	_, err := s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+
+	key := []byte("test")
+	owner, err := nodes[0].Lookup(key)
+	require.NoError(t, err)
+	for _, n := range nodes[1:] {
+		other, err := n.Lookup(key)
+		require.NoError(t, err)
+		assert.Equal(t, owner.Id, other.Id)
+	}
+}
+
+func TestSimulatedCrashes(t *testing.T) {
+	s := sim.New(sim.Config{
+		Seed:       2,
+		MinLatency: time.Millisecond,
+		MaxLatency: 20 * time.Millisecond,
+		Loss:       0.01,
+	})
+
+	nodes := spawnRing(t, s, 300, time.Second, func(c *concord.Config) {
+		c.SuccessorCount = 8
+	})
+	_, err := s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+
+	// crash every tenth node at once.
+	for i := 5; i < len(nodes); i += 10 {
+		s.Crash(nodes[i])
+	}
+
+	took, err := s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+	t.Logf("recovered from crashes after %s", took)
+}
+
+func TestSimulatedPartition(t *testing.T) {
+	s := sim.New(sim.Config{
+		Seed:       4,
+		MinLatency: time.Millisecond,
+		MaxLatency: 20 * time.Millisecond,
+	})
+
+	nodes := spawnRing(t, s, 20, time.Second, func(c *concord.Config) {
+		c.SuccessorCount = 8
+	})
+	_, err := s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+
+	// each side drops the nodes it can no longer reach.
+	groups := [][]*concord.Concord{nodes[:10], nodes[10:]}
+	s.Partition(groups...)
+	s.Run(time.Minute)
+
+	for _, group := range groups {
+		names := make(map[string]bool)
+		for _, n := range group {
+			names[n.Name()] = true
+		}
+		for _, n := range group {
+			for _, succ := range n.Successors() {
+				assert.True(t, names[succ.Name], "%s kept %s across the partition", n.Name(), succ.Name)
+			}
+		}
+	}
+}
+
+func TestSimulationIsDeterministic(t *testing.T) {
+	run := func() []string {
+		s := sim.New(sim.Config{
+			Seed:       3,
+			MinLatency: time.Millisecond,
+			MaxLatency: 50 * time.Millisecond,
+			Loss:       0.05,
+		})
+
+		nodes := spawnRing(t, s, 100, 100*time.Millisecond, func(c *concord.Config) {
+			c.SuccessorCount = 5
+		})
+		for i := 0; i < len(nodes); i += 7 {
+			s.Crash(nodes[i])
+		}
+		s.Run(30 * time.Second)
+
+		// a snapshot of the ring mid-recovery.
+		var snapshot []string
+		for _, n := range s.Nodes() {
+			pred, _ := n.Predecessor()
+			snapshot = append(snapshot, fmt.Sprintf("%s: %v %v", n.Name(), pred.Name, n.Successors()))
+		}
+		return snapshot
+	}
+
+	assert.Equal(t, run(), run())
+}
+
+// a partition shorter than the failure detector takes to declare peers dead
+// leaves the ranges alone.
+func TestTransientPartitionKeepsRanges(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		detector func() concord.FailureDetector
+		vnodes   uint
+		changes  bool
+	}{
+		{"drop on first failure", func() concord.FailureDetector { return &concord.ThresholdDetector{Dead: 1} }, 1, true},
+		{"threshold", func() concord.FailureDetector { return &concord.ThresholdDetector{} }, 1, false},
+		{"phi accrual", func() concord.FailureDetector {
+			return &concord.PhiAccrualDetector{FirstInterval: time.Second}
+		}, 1, false},
+		// every virtual node probes the same processes; a round still counts
+		// once.
+		{"threshold with virtual nodes", func() concord.FailureDetector { return &concord.ThresholdDetector{} }, 8, false},
+		{"phi accrual with virtual nodes", func() concord.FailureDetector {
+			return &concord.PhiAccrualDetector{FirstInterval: time.Second}
+		}, 8, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := sim.New(sim.Config{
+				Seed:       1,
+				MinLatency: time.Millisecond,
+				MaxLatency: 5 * time.Millisecond,
+				Timeout:    100 * time.Millisecond,
+			})
+
+			var changes atomic.Int64
+			nodes := spawnRing(t, s, 20, time.Second, func(config *concord.Config) {
+				config.StabilizeInterval = time.Second
+				config.VirtualNodes = tc.vnodes
+				config.FailureDetector = tc.detector()
+				config.OnRangeChange = func(concord.Range) { changes.Add(1) }
+			})
+
+			_, err := s.Settle(10 * time.Minute)
+			require.NoError(t, err)
+			s.Run(time.Minute)
+
+			changes.Store(0)
+			s.Partition(nodes[:10])
+			s.Run(1500 * time.Millisecond)
+			s.Heal()
+
+			_, err = s.Settle(10 * time.Minute)
+			require.NoError(t, err)
+
+			if tc.changes {
+				assert.Positive(t, changes.Load())
+			} else {
+				assert.Zero(t, changes.Load())
+			}
+		})
+	}
+}
+
+func TestIsolatedNodeRejoins(t *testing.T) {
+	s := sim.New(sim.Config{
+		Seed:       3,
+		MinLatency: time.Millisecond,
+		MaxLatency: 5 * time.Millisecond,
+		Timeout:    100 * time.Millisecond,
+	})
+
+	var events []bool
+	nodes := spawnRing(t, s, 10, time.Second, func(config *concord.Config) {
+		config.StabilizeInterval = time.Second
+		if config.Name == "node-3" {
+			config.OnIsolation = func(isolated bool) { events = append(events, isolated) }
+		}
+	})
+	_, err := s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+
+	// cut a node off long enough for it to give up on all its successors,
+	// and for the rest of the ring to give up on it.
+	isolated := nodes[3]
+	s.Partition(nodes[3:4])
+	_, ok := s.RunUntil(func() bool {
+		succs := isolated.Successors()
+		return len(succs) == 1 && succs[0].Id == isolated.Id()
+	}, 100*time.Millisecond, time.Minute)
+	require.True(t, ok, "node never noticed it was isolated")
+	s.Run(30 * time.Second)
+	assert.Equal(t, []bool{true}, events)
+
+	s.Heal()
+	_, err = s.Settle(10 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, events)
+}
@@ -14,13 +14,13 @@ func AssertConsistentRing(t assert.TestingT, nodes []*concord.Concord) {
 	assert.NotEmpty(t, nodes, "node list must not be empty")
 
 	expectedSize := len(nodes)
-	idToNode := make(map[uint64]*concord.Concord)
+	idToNode := make(map[concord.ID]*concord.Concord)
 
 	for _, node := range nodes {
 		idToNode[node.Id()] = node
 	}
 
-	visitedIds := make(map[uint64]bool)
+	visitedIds := make(map[concord.ID]bool)
 	current := nodes[0]
 
 	for i := 0; i <= expectedSize; i++ {
@@ -35,16 +35,20 @@ func AssertConsistentRing(t assert.TestingT, nodes []*concord.Concord) {
 		visitedIds[currentID] = true
 
 		successors := current.Successors()
-		assert.NotEmpty(t, successors, "Node %d has no successors", currentID)
+		if !assert.NotEmpty(t, successors, "Node %v has no successors", currentID) {
+			return
+		}
 
 		successor := successors[0]
 		next, ok := idToNode[successor.Id]
-		assert.True(t, ok, "Successor %d is not a known node (from node %d)", successor.Id, currentID)
+		if !assert.True(t, ok, "Successor %v is not a known node (from node %v)", successor.Id, currentID) {
+			return
+		}
 
 		// Check bidirectional consistency
 		pred, ok := next.Predecessor()
-		assert.True(t, ok, "Node %d has no predecessor (successor of node %d)", successor.Id, currentID)
-		assert.Equal(t, currentID, pred.Id, "Inconsistent links: Node %d → successor %d, but successor's predecessor is %d",
+		assert.True(t, ok, "Node %v has no predecessor (successor of node %v)", successor.Id, currentID)
+		assert.Equal(t, currentID, pred.Id, "Inconsistent links: Node %v → successor %v, but successor's predecessor is %v",
 			currentID, successor.Id, pred.Id)
 
 		current = next
@@ -67,30 +71,32 @@ func AssertConsistentLookupForKey(t assert.TestingT, ctx context.Context, nodes
 		assert.NoError(t, err, "Lookup failed on node %d", i)
 		assert.NotNil(t, actualResult, "Node %d returned nil", i)
 		assert.Equal(t, expectedResult.Id, actualResult.Id,
-			"Node %d returned different server: expected %d, got %d", i, expectedResult.Id, actualResult.Id)
+			"Node %d returned different server: expected %v, got %v", i, expectedResult.Id, actualResult.Id)
 	}
 }
 
-// AssertFullRangeCover verifies that all nodes' ranges cover the entire ring without gaps
+// AssertFullRangeCover verifies that all nodes' ranges, including those of their
+// virtual nodes, cover the entire ring without gaps
 func AssertFullRangeCover(t assert.TestingT, nodes []*concord.Concord) {
 
 	assert.NotEmpty(t, nodes, "node list must not be empty")
 
 	type rangeData struct {
-		start uint64
-		end   uint64
+		start concord.ID
+		end   concord.ID
 		idx   int
 	}
 
 	var ranges []rangeData
 	for i, node := range nodes {
-		r := node.Range()
-		ranges = append(ranges, rangeData{start: r.Start, end: r.End, idx: i})
+		for _, r := range node.Ranges() {
+			ranges = append(ranges, rangeData{start: r.Start, end: r.End, idx: i})
+		}
 	}
 
 	// Sort ranges by start
 	sort.Slice(ranges, func(i, j int) bool {
-		return ranges[i].start < ranges[j].start
+		return ranges[i].start.Cmp(ranges[j].start) < 0
 	})
 
 	// Check continuity
@@ -99,7 +105,31 @@ func AssertFullRangeCover(t assert.TestingT, nodes []*concord.Concord) {
 		next := ranges[(i+1)%len(ranges)]
 
 		assert.Equal(t, current.end, next.start,
-			"Range at index %d (%d, %d] must connect to next range's start (%d)",
+			"Range at index %d (%v, %v] must connect to next range's start (%v)",
 			current.idx, current.start, current.end, next.start)
 	}
 }
+
+// AssertSuccessorLists verifies that the successor list of every node holds the
+// nodes following it on the ring, in order
+func AssertSuccessorLists(t assert.TestingT, nodes []*concord.Concord) {
+	assert.NotEmpty(t, nodes, "node list must not be empty")
+
+	ring := make([]concord.ID, len(nodes))
+	for i, node := range nodes {
+		ring[i] = node.Id()
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		return ring[i].Cmp(ring[j]) < 0
+	})
+
+	for _, node := range nodes {
+		i := sort.Search(len(ring), func(i int) bool {
+			return ring[i].Cmp(node.Id()) >= 0
+		})
+		for j, s := range node.Successors() {
+			expected := ring[(i+j+1)%len(ring)]
+			assert.Equal(t, expected, s.Id, "Successor %d of node %v is %v, expected %v", j, node.Id(), s.Id, expected)
+		}
+	}
+}
@@ -9,8 +9,14 @@ import (
 	"log/slog"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ollelogdahl/concord"
+	"github.com/ollelogdahl/concord/sim"
+	"github.com/ollelogdahl/concord/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func hash(data []byte) uint64 {
@@ -21,12 +27,23 @@ func hash(data []byte) uint64 {
 type ConcordSetup struct {
 	startPort atomic.Int32
 	nodes     []*concord.Concord
+	network   *concord.MemoryNetwork
+	clock     *sim.Clock
+
+	// TCP, if set, connects nodes with gRPC over localhost and runs them on the
+	// wall clock, instead of an in-memory network and a virtual clock.
+	TCP bool
+
+	// Configure, if set, adjusts the config of every node before it is created.
+	Configure func(config *concord.Config)
 }
 
 func NewConcordSetup() *ConcordSetup {
 	return &ConcordSetup{
 		startPort: atomic.Int32{},
 		nodes:     make([]*concord.Concord, 0),
+		network:   concord.NewMemoryNetwork(),
+		clock:     sim.NewClock(),
 	}
 }
 
@@ -62,7 +79,10 @@ func (w *testLogWriter) Write(p []byte) (n int, err error) {
 // CreateNode creates a single Concord node
 func (cs *ConcordSetup) CreateNode(t *testing.T, ctx context.Context) (*concord.Concord, error) {
 	port := cs.startPort.Add(1)
-	addr := fmt.Sprintf("localhost:%d", 15000+port)
+	addr := fmt.Sprintf("node-%d", port)
+	if cs.TCP {
+		addr = fmt.Sprintf("localhost:%d", 15000+port)
+	}
 
 	config := concord.Config{
 		Name:       fmt.Sprintf("node-%d", port),
@@ -70,6 +90,13 @@ func (cs *ConcordSetup) CreateNode(t *testing.T, ctx context.Context) (*concord.
 		AdvAddr:    addr,
 		LogHandler: slog.NewTextHandler(&testLogWriter{t}, nil),
 	}
+	if !cs.TCP {
+		config.Transport = cs.network.Transport()
+		config.Clock = cs.clock
+	}
+	if cs.Configure != nil {
+		cs.Configure(&config)
+	}
 
 	concord := concord.New(config)
 	cs.nodes = append(cs.nodes, concord)
@@ -88,6 +115,46 @@ func (cs *ConcordSetup) StopNodes(ctx context.Context, nodes []*concord.Concord)
 	return nil
 }
 
+// Eventually runs the nodes until check passes, for at most waitFor. Nodes on
+// the virtual clock are advanced in steps from the calling goroutine, so no
+// time is spent waiting.
+func (cs *ConcordSetup) Eventually(t *testing.T, check func(ct assert.TestingT), waitFor time.Duration) {
+	const tick = 100 * time.Millisecond
+
+	if cs.TCP {
+		assert.EventuallyWithT(t, func(ct *assert.CollectT) { check(ct) }, waitFor, tick)
+		return
+	}
+
+	for waited := time.Duration(0); waited < waitFor; waited += tick {
+		var rec testutil.Recorder
+		check(&rec)
+		if !rec.Failed {
+			return
+		}
+		cs.clock.Advance(tick)
+	}
+	check(t)
+}
+
+// Dial connects to the node at addr, over the network the nodes share.
+func (cs *ConcordSetup) Dial(addr string) (grpc.ClientConnInterface, error) {
+	if cs.TCP {
+		return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	return cs.network.Transport().Dial(addr)
+}
+
+// Advance moves the virtual clock of the nodes ahead by d.
+func (cs *ConcordSetup) Advance(d time.Duration) {
+	cs.clock.Advance(d)
+}
+
+// Now returns the virtual time of the nodes.
+func (cs *ConcordSetup) Now() time.Time {
+	return cs.clock.Now()
+}
+
 // GenerateRandomKeys generates count random keys of keySize bytes each
 func (cs *ConcordSetup) GenerateRandomKeys(count int, keySize int) ([][]byte, error) {
 	keys := make([][]byte, count)
@@ -2,12 +2,27 @@ package system_test
 
 import (
 	"context"
+	"net"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ollelogdahl/concord"
+	"github.com/ollelogdahl/concord/rpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func TestBasicClusterFormation(t *testing.T) {
@@ -25,17 +40,69 @@ func TestBasicClusterFormation(t *testing.T) {
 	err = setup.ConnectCluster(ctx, nodes)
 	require.NoError(t, err, "failed to connect cluster")
 
-	// Allow time for stabilization
-	time.Sleep(1 * time.Second)
-
 	key := []byte("test")
 
 	// Assert ring consistency
-	assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+	setup.Eventually(t, func(ct assert.TestingT) {
 		AssertConsistentRing(ct, nodes)
 		AssertConsistentLookupForKey(ct, ctx, nodes, key)
 		AssertFullRangeCover(ct, nodes)
-	}, 10*time.Second, 100*time.Millisecond)
+	}, 10*time.Second)
+}
+
+func TestClusterFormationOverTCP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.TCP = true
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, []byte("test"))
+		AssertFullRangeCover(ct, nodes)
+	}, 10*time.Second)
+}
+
+func TestClusterOnSystemChosenPorts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.TCP = true
+	setup.Configure = func(config *concord.Config) {
+		config.BindAddr = "127.0.0.1:0"
+		config.AdvAddr = "{host}:{port}"
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	addrs := make(map[string]bool)
+	for _, n := range nodes {
+		assert.NotContains(t, n.Address(), "{")
+		assert.NotRegexp(t, `:0$`, n.Address())
+		addrs[n.Address()] = true
+	}
+	assert.Len(t, addrs, len(nodes))
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, []byte("test"))
+		AssertFullRangeCover(ct, nodes)
+	}, 10*time.Second)
 }
 
 func TestNodeShutdown(t *testing.T) {
@@ -59,10 +126,10 @@ func TestNodeShutdown(t *testing.T) {
 	ns := []*concord.Concord{nodes[0], nodes[2]}
 
 	// Assert ring consistency
-	assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+	setup.Eventually(t, func(ct assert.TestingT) {
 		AssertConsistentRing(ct, ns)
 		AssertFullRangeCover(ct, ns)
-	}, 10*time.Second, 100*time.Millisecond)
+	}, 10*time.Second)
 }
 
 func Test2NodeShutdown(t *testing.T) {
@@ -89,8 +156,1487 @@ func Test2NodeShutdown(t *testing.T) {
 	ns := []*concord.Concord{nodes[0]}
 
 	// Assert ring consistency
-	assert.EventuallyWithT(t, func(ct *assert.CollectT) {
+	setup.Eventually(t, func(ct assert.TestingT) {
 		AssertConsistentRing(ct, ns)
 		AssertFullRangeCover(ct, ns)
-	}, 10*time.Second, 100*time.Millisecond)
+	}, 10*time.Second)
+}
+
+func TestNodeLeave(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	err = nodes[1].Leave(ctx)
+	require.NoError(t, err, "failed to leave with node 2")
+
+	// the neighbours are updated before Leave returns; no stabilization needed.
+	ns := []*concord.Concord{nodes[0], nodes[2]}
+	AssertConsistentRing(t, ns)
+	AssertFullRangeCover(t, ns)
+}
+
+func TestLeaveWithoutServerRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 2)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	conn, err := setup.Dial(nodes[0].Address())
+	require.NoError(t, err)
+	_, err = rpc.NewChordServiceClient(conn).Leave(ctx, &rpc.LeaveReq{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+}
+
+func TestJoinAnySkipsDeadSeeds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 2)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	require.NoError(t, nodes[0].Create(), "failed to create cluster")
+
+	seeds := []string{"localhost:1", nodes[0].Address(), "localhost:2"}
+	err = nodes[1].JoinAny(ctx, seeds)
+	require.NoError(t, err, "failed to join through seed list")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertFullRangeCover(ct, nodes)
+	}, 10*time.Second)
+}
+
+func TestJoinAnyReportsEverySeed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 1)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = nodes[0].JoinAny(ctx, []string{"localhost:1", "localhost:2"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "seed localhost:1")
+	assert.Contains(t, err.Error(), "seed localhost:2")
+}
+
+func TestLookupErrorCarriedAcrossHops(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.SuccessorCount = 1
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 2)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// a key of the first node is resolved by the second one.
+	keys, err := setup.GenerateRandomKeys(100, 16)
+	require.NoError(t, err)
+	var key []byte
+	for _, k := range keys {
+		owner, err := nodes[0].LookupContext(ctx, k)
+		require.NoError(t, err)
+		if owner.Id == nodes[0].Id() {
+			key = k
+			break
+		}
+	}
+	require.NotNil(t, key, "no key owned by the first node")
+
+	// restarted, the second node answers, but is no longer in the ring.
+	require.NoError(t, nodes[1].Stop())
+	require.NoError(t, nodes[1].Start())
+
+	_, err = nodes[0].LookupContext(ctx, key)
+	assert.ErrorIs(t, err, concord.ErrAllContendersFailed)
+	assert.ErrorIs(t, err, concord.ErrNotReady)
+}
+
+func TestLookupReplicas(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	key := []byte("test")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+
+		owner, err := nodes[0].Lookup(key)
+		assert.NoError(ct, err)
+
+		// more replicas than nodes; every node is returned exactly once.
+		replicas, err := nodes[0].LookupReplicas(ctx, key, 5)
+		assert.NoError(ct, err)
+		if assert.Len(ct, replicas, 3) {
+			assert.Equal(ct, owner, replicas[0])
+			assert.NotEqual(ct, replicas[1].Id, replicas[2].Id)
+		}
+	}, 10*time.Second)
+}
+
+func TestLookupTrace(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	for i := range 10 {
+		key := []byte{byte(i)}
+
+		owner, hops, err := nodes[0].LookupTrace(ctx, key)
+		require.NoError(t, err)
+		require.NotEmpty(t, hops)
+
+		expected, err := nodes[0].Lookup(key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, owner)
+
+		assert.Equal(t, nodes[0].Id(), hops[0].Node.Id, "trace must start at the origin")
+		assert.Equal(t, concord.HopResolved, hops[len(hops)-1].Finger, "trace must end at the resolving node")
+		assert.LessOrEqual(t, len(hops), len(nodes))
+	}
+}
+
+func TestRoutingLoopRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const maxHops = 8
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.MaxHops = maxHops
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 8)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, time.Minute)
+	// let every node fix its fingers, so lookups take more than one hop.
+	setup.Advance(time.Minute)
+
+	origin := nodes[0]
+	conn, err := setup.Dial(origin.Address())
+	require.NoError(t, err)
+	cli := rpc.NewChordServiceClient(conn)
+
+	// out of hops.
+	_, err = cli.FindSuccessor(ctx, &rpc.FindReq{Id: origin.Id().Uint64() + 1, Hops: maxHops + 1})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// back at a node it already visited.
+	_, err = cli.FindSuccessor(ctx, &rpc.FindReq{Id: origin.Id().Uint64() + 1, Visited: []uint64{origin.Id().Uint64()}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// a lookup forwarded through a node that rejects it falls back to the
+	// successor list, and still resolves.
+	ring := slices.SortedFunc(slices.Values(nodes), func(a, b *concord.Concord) int {
+		return a.Id().Cmp(b.Id())
+	})
+	tried := false
+	for i, owner := range ring {
+		// just past the predecessor of the owner.
+		pred := ring[(i+len(ring)-1)%len(ring)]
+		target := &rpc.FindReq{Id: pred.Id().Uint64() + 1, Trace: true}
+		resp, err := cli.FindSuccessor(ctx, target)
+		require.NoError(t, err)
+		require.Equal(t, owner.Id().Uint64(), resp.Server.Id)
+
+		// the last hop, the predecessor of the owner, is the only one able to
+		// resolve the lookup; reject an earlier one.
+		if len(resp.Hops) < 3 {
+			continue
+		}
+		first := resp.Hops[1].Server
+		tried = true
+
+		target.Visited = []uint64{first.Id}
+		resp, err = cli.FindSuccessor(ctx, target)
+		require.NoError(t, err)
+		assert.Equal(t, owner.Id().Uint64(), resp.Server.Id)
+		assert.Equal(t, int32(concord.HopSuccessor), resp.Hops[0].Finger)
+		for _, hop := range resp.Hops {
+			assert.NotEqual(t, first.Id, hop.Server.Id, "lookup went through a rejecting node")
+		}
+	}
+	assert.True(t, tried, "no lookup took more than two hops")
+}
+
+func TestIterativeLookup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.LookupMode = concord.LookupIterative
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	key := []byte("test")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, key)
+	}, 10*time.Second)
+
+	owner, hops, err := nodes[0].LookupTrace(ctx, key)
+	require.NoError(t, err)
+	require.NotEmpty(t, hops)
+
+	expected, err := nodes[1].Lookup(key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, owner)
+	assert.Equal(t, nodes[0].Id(), hops[0].Node.Id, "trace must start at the origin")
+	assert.Equal(t, concord.HopResolved, hops[len(hops)-1].Finger, "trace must end at the resolving node")
+}
+
+func TestLookupRoutesThroughSuccessorList(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 100 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 6)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	// the last node to join has hardly fixed any finger yet; they point at its
+	// first successor.
+	last := nodes[len(nodes)-1]
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertSuccessorLists(ct, nodes)
+	}, 10*time.Second)
+	succs := last.Successors()
+	require.Len(t, succs, 3)
+
+	keys, err := setup.GenerateRandomKeys(2000, 16)
+	require.NoError(t, err)
+	checked := 0
+	for _, key := range keys {
+		owner, hops, err := last.LookupTrace(ctx, key)
+		require.NoError(t, err)
+		if owner.Id != succs[2].Id {
+			continue
+		}
+
+		// the second successor precedes the key, and resolves it.
+		require.Len(t, hops, 2, "the lookup did not skip ahead through the successor list")
+		assert.Equal(t, succs[1].Id, hops[1].Node.Id)
+		checked++
+	}
+	require.NotZero(t, checked, "no key is owned by the third successor")
+}
+
+func TestLookupStopsAfterContenderFailsEveryRoute(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	faults := &lookupFaults{}
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 100 * time.Millisecond
+		config.Transport = faultyTransport{Transport: config.Transport, from: config.AdvAddr, faults: faults}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 6)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// a key the first hop forwards onwards.
+	origin := nodes[0]
+	keys, err := setup.GenerateRandomKeys(100, 16)
+	require.NoError(t, err)
+	var key []byte
+	for _, k := range keys {
+		_, hops, err := origin.LookupTrace(ctx, k)
+		require.NoError(t, err)
+		if len(hops) > 2 {
+			key = k
+			break
+		}
+	}
+	require.NotNil(t, key, "no lookup takes more than one hop")
+
+	// every other node fails to forward traced lookups; the first hop reports
+	// that all its contenders failed, and the origin gives up.
+	faults.fail(origin.Address())
+	_, _, err = origin.LookupTrace(ctx, key)
+	assert.ErrorIs(t, err, concord.ErrAllContendersFailed)
+	assert.Equal(t, 1, faults.sent(origin.Address()), "the origin tried other contenders")
+}
+
+// counts the traced lookups each node forwards, and fails those of every node
+// but one.
+type lookupFaults struct {
+	mu     sync.Mutex
+	except string
+	counts map[string]int
+}
+
+func (f *lookupFaults) fail(except string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.except = except
+	f.counts = make(map[string]int)
+}
+
+func (f *lookupFaults) sent(from string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[from]
+}
+
+// reports whether a traced lookup forwarded by from fails.
+func (f *lookupFaults) forward(from string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.counts == nil {
+		return false
+	}
+	f.counts[from]++
+	return from != f.except
+}
+
+type faultyTransport struct {
+	concord.Transport
+	from   string
+	faults *lookupFaults
+}
+
+func (t faultyTransport) Dial(addr string) (concord.ClientConn, error) {
+	conn, err := t.Transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return faultyConn{ClientConn: conn, from: t.from, faults: t.faults}, nil
+}
+
+type faultyConn struct {
+	concord.ClientConn
+	from   string
+	faults *lookupFaults
+}
+
+func (c faultyConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	if req, ok := args.(*rpc.FindReq); ok && req.Trace && c.faults.forward(c.from) {
+		return status.Error(codes.Unavailable, "injected fault")
+	}
+	return c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+}
+
+func TestVirtualNodes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.VirtualNodes = 4
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	names := make(map[string]bool)
+	for _, node := range nodes {
+		names[node.Name()] = true
+		assert.Len(t, node.VirtualNodes(), 4)
+	}
+
+	key := []byte("test")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		for _, node := range nodes {
+			assert.Len(ct, node.Ranges(), 4)
+		}
+		AssertFullRangeCover(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, key)
+
+		// lookups resolve to one of the physical servers.
+		owner, err := nodes[0].Lookup(key)
+		assert.NoError(ct, err)
+		assert.True(ct, names[owner.Name], "owner %s is not a physical server", owner.Name)
+
+	}, 20*time.Second)
+
+	// replicas are on distinct servers, not just distinct virtual nodes.
+	for i := range 20 {
+		replicas, err := nodes[0].LookupReplicas(ctx, []byte{byte(i)}, 3)
+		require.NoError(t, err)
+		addrs := make(map[string]bool)
+		for _, r := range replicas {
+			assert.False(t, addrs[r.Address], "replica %s is on a server already in the set", r.Name)
+			addrs[r.Address] = true
+		}
+	}
+}
+
+func TestWideIdentifiers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.IDFunc = concord.SHA256Hash(160)
+		config.HashBits = 160
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 4)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertFullRangeCover(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, []byte("test"))
+	}, 20*time.Second)
+}
+
+func TestJoinIDCollision(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	id := concord.IDFromUint64(1234)
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.ID = &id
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 2)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	require.NoError(t, nodes[0].Create())
+
+	err = nodes[1].Join(ctx, nodes[0].Address())
+	assert.ErrorIs(t, err, concord.ErrIDCollision)
+	assert.NoError(t, ctx.Err(), "collision must fail the join without retrying")
+}
+
+func TestAdminService(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.Admin = true
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	conn, err := setup.Dial(nodes[1].Address())
+	require.NoError(t, err)
+	admin := rpc.NewConcordAdminClient(conn)
+
+	_, err = admin.ForceStabilize(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	_, err = admin.FixAllFingers(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	info, err := admin.GetInfo(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, nodes[1].Name(), info.Name)
+	assert.Equal(t, uint32(64), info.HashBits)
+	assert.Equal(t, 200*time.Millisecond, info.StabilizeInterval.AsDuration())
+	assert.Positive(t, info.Uptime.AsDuration())
+	require.Len(t, info.VirtualNodes, 1)
+
+	vn := info.VirtualNodes[0]
+	assert.True(t, vn.Ready)
+	assert.Equal(t, nodes[1].Id().Uint64(), vn.Self.Id)
+	assert.Equal(t, nodes[1].Range().End.Uint64(), vn.Range.End)
+	assert.Equal(t, nodes[1].Successors()[0].Name, vn.Successors[0].Name)
+	assert.Len(t, vn.Fingers, 64)
+
+	names := map[string]bool{}
+	for _, n := range nodes {
+		names[n.Name()] = true
+	}
+	for _, f := range vn.Fingers {
+		require.NotNil(t, f.Node, "finger starting at %d is not set", f.Start)
+		assert.True(t, names[f.Node.Name], "finger starting at %d points at unknown node %s", f.Start, f.Node.Name)
+	}
+
+	stats, err := admin.GetStats(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.Lookups, uint64(64), "fixing all fingers looks each one up")
+	assert.Equal(t, nodes[1].Stats().Lookups, stats.Lookups)
+
+	// nodes without the admin service enabled do not serve it.
+	plain := NewConcordSetup()
+	node, err := plain.CreateNode(t, ctx)
+	require.NoError(t, err)
+	require.NoError(t, node.Start())
+	defer node.Stop()
+
+	conn, err = plain.Dial(node.Address())
+	require.NoError(t, err)
+	_, err = rpc.NewConcordAdminClient(conn).GetInfo(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	metrics := make(map[string]*concord.PrometheusMetrics)
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		m := concord.NewPrometheusMetrics()
+		metrics[config.Name] = m
+		config.Metrics = m
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 5)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	keys, err := setup.GenerateRandomKeys(20, 16)
+	require.NoError(t, err)
+	for _, key := range keys {
+		_, err := nodes[0].LookupContext(ctx, key)
+		require.NoError(t, err)
+	}
+
+	scrape := func(name string) string {
+		rec := httptest.NewRecorder()
+		metrics[name].ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		return rec.Body.String()
+	}
+
+	body := scrape(nodes[0].Name())
+	assert.Regexp(t, `concord_lookups_total\{result="success"\} [1-9]`, body)
+	assert.Regexp(t, `concord_lookup_hops_count [1-9]`, body)
+	assert.Regexp(t, `concord_forwarded_requests_total\{peer="node-\d+"\} [1-9]`, body)
+	assert.Regexp(t, `concord_stabilize_duration_seconds_count [1-9]`, body)
+	assert.Contains(t, body, `concord_successors{vnode="0"} 3`)
+	assert.Regexp(t, `concord_predecessor_changes_total [1-9]`, body)
+	assert.Regexp(t, `concord_range_changes_total [1-9]`, body)
+	assert.Regexp(t, `concord_connection_cache_size [1-9]`, body)
+	assert.Regexp(t, `concord_connection_cache_misses_total [1-9]`, body)
+
+	// stopping a node makes the stabilization of its predecessor fail.
+	pred, ok := nodes[2].Predecessor()
+	require.True(t, ok)
+	require.NoError(t, nodes[2].Stop())
+
+	setup.Advance(time.Second)
+	assert.Regexp(t, `concord_stabilize_failures_total [1-9]`, scrape(pred.Name))
+}
+
+func TestTracing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(ctx)
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.TracerProvider = provider
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 8)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	exporter.Reset()
+
+	// a lookup made while handling a request of the application joins its trace.
+	appCtx, appSpan := provider.Tracer("app").Start(ctx, "request")
+	keys, err := setup.GenerateRandomKeys(10, 16)
+	require.NoError(t, err)
+	for _, key := range keys {
+		_, err := nodes[0].LookupContext(appCtx, key)
+		require.NoError(t, err)
+	}
+	appSpan.End()
+
+	traceID := appSpan.SpanContext().TraceID()
+	spans := map[string]int{}
+	for _, s := range exporter.GetSpans() {
+		if s.SpanContext.TraceID() == traceID {
+			spans[s.Name]++
+		}
+	}
+	assert.Equal(t, 10, spans["concord.Lookup"])
+	assert.Positive(t, spans["concord.ChordService/FindSuccessor"], "forwarded lookups must be traced on both ends")
+	assert.Zero(t, spans["concord.Stabilize"])
+
+	exporter.Reset()
+	setup.Advance(time.Second)
+
+	names := map[string]bool{}
+	for _, s := range exporter.GetSpans() {
+		names[s.Name] = true
+	}
+	assert.True(t, names["concord.Stabilize"])
+	assert.True(t, names["concord.ChordService/Notify"])
+	assert.True(t, names["concord.Rectify"])
+}
+
+// counts the connections dialed through a transport that are not closed yet.
+type countingTransport struct {
+	concord.Transport
+	open *atomic.Int64
+}
+
+func (t countingTransport) Dial(addr string) (concord.ClientConn, error) {
+	conn, err := t.Transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	t.open.Add(1)
+	return &countingConn{ClientConn: conn, open: t.open}, nil
+}
+
+type countingConn struct {
+	concord.ClientConn
+	open   *atomic.Int64
+	closed atomic.Bool
+}
+
+func (c *countingConn) Close() error {
+	if !c.closed.Swap(true) {
+		c.open.Add(-1)
+	}
+	return c.ClientConn.Close()
+}
+
+func TestConnectionCacheBounded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var open atomic.Int64
+	metrics := concord.NewPrometheusMetrics()
+	first := true
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 200 * time.Millisecond
+		if first {
+			config.Transport = countingTransport{Transport: config.Transport, open: &open}
+			config.MaxConnections = 3
+			// evict on the first failure, so the test need not wait for
+			// the stopped successor to be probed again.
+			config.MaxConnectionFailures = 1
+			config.Metrics = metrics
+			first = false
+		}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 8)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// lookups reach most of the ring, but only a few connections stay open.
+	keys, err := setup.GenerateRandomKeys(50, 16)
+	require.NoError(t, err)
+	for _, key := range keys {
+		_, err := nodes[0].LookupContext(ctx, key)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, open.Load(), int64(3))
+	}
+
+	// connections to a node that stopped are dropped once they keep failing.
+	succ := nodes[0].Successors()[0]
+	for _, n := range nodes {
+		if n.Name() == succ.Name {
+			require.NoError(t, n.Stop())
+		}
+	}
+	setup.Advance(2 * time.Second)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Regexp(t, `concord_connection_cache_evictions_total\{reason="capacity"\} [1-9]`, rec.Body.String())
+	assert.Regexp(t, `concord_connection_cache_evictions_total\{reason="failures"\} [1-9]`, rec.Body.String())
+
+	require.NoError(t, nodes[0].Stop())
+	assert.Zero(t, open.Load(), "stopping a node closes all of its connections")
+}
+
+// runs a function while the next request is in flight, and fails requests
+// that find their connection closed once they return, as gRPC does.
+type interruptingTransport struct {
+	concord.Transport
+	during *func()
+}
+
+func (t interruptingTransport) Dial(addr string) (concord.ClientConn, error) {
+	conn, err := t.Transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &interruptingConn{ClientConn: conn, during: t.during}, nil
+}
+
+type interruptingConn struct {
+	concord.ClientConn
+	during *func()
+	closed atomic.Bool
+}
+
+func (c *interruptingConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	if f := *c.during; f != nil {
+		*c.during = nil
+		f()
+	}
+	err := c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	if c.closed.Load() {
+		return status.Error(codes.Canceled, "connection closed")
+	}
+	return err
+}
+
+func (c *interruptingConn) Close() error {
+	c.closed.Store(true)
+	return c.ClientConn.Close()
+}
+
+func TestConnectionEvictedWhileInUse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var during func()
+	metrics := concord.NewPrometheusMetrics()
+	first := true
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		if first {
+			config.Transport = interruptingTransport{Transport: config.Transport, during: &during}
+			config.MaxConnections = 1
+			config.Metrics = metrics
+			first = false
+		}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+	// let the first node fix its fingers, so it reaches either peer directly.
+	setup.Advance(time.Minute)
+
+	// keys the first node forwards to either peer; one lookup is sent while
+	// the other is in flight, and evicts its connection.
+	keys, err := setup.GenerateRandomKeys(100, 16)
+	require.NoError(t, err)
+	byPeer := make(map[string][]byte)
+	firstHop := make(map[string]concord.Hop)
+	for _, key := range keys {
+		_, hops, err := nodes[0].LookupTrace(ctx, key)
+		require.NoError(t, err)
+		if len(hops) > 1 {
+			byPeer[hops[1].Node.Address] = key
+			firstHop[string(key)] = hops[0]
+		}
+	}
+	require.Len(t, byPeer, 2, "lookups do not reach both peers")
+
+	var inFlight, evicting []byte
+	for _, key := range byPeer {
+		if inFlight == nil {
+			inFlight = key
+		} else {
+			evicting = key
+		}
+	}
+
+	// the connection for the lookup in flight is dialed afresh.
+	_, err = nodes[0].LookupContext(ctx, evicting)
+	require.NoError(t, err)
+	during = func() {
+		_, err := nodes[0].LookupContext(ctx, evicting)
+		assert.NoError(t, err)
+	}
+
+	_, hops, err := nodes[0].LookupTrace(ctx, inFlight)
+	require.NoError(t, err)
+	assert.Nil(t, during, "the lookup did not open a connection")
+	// the successor list may be the route taken anyway, so the first hop is
+	// compared to the one before the eviction.
+	assert.Equal(t, firstHop[string(inFlight)].Finger, hops[0].Finger, "the lookup fell back after its connection was evicted")
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Regexp(t, `concord_connection_cache_evictions_total\{reason="capacity"\} [1-9]`, rec.Body.String())
+}
+
+func TestStabilizationPastBlackHole(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.TCP = true
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 100 * time.Millisecond
+		config.RPCTimeout = 300 * time.Millisecond
+		config.RetryPolicy = &concord.RetryPolicy{
+			Attempts: 2,
+			Backoff:  20 * time.Millisecond,
+			Jitter:   0.5,
+		}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 4)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// replace a node by a peer that accepts connections, but never answers.
+	hole := nodes[2]
+	require.NoError(t, hole.Stop())
+	defer blackHole(t, hole.Address()).Close()
+
+	remaining := []*concord.Concord{nodes[0], nodes[1], nodes[3]}
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, remaining)
+		AssertFullRangeCover(ct, remaining)
+		AssertConsistentLookupForKey(ct, ctx, remaining, []byte("test"))
+	}, 15*time.Second)
+}
+
+func TestRequestTimeoutOnVirtualClock(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var stalled atomic.Bool
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 200 * time.Millisecond
+		config.RPCTimeout = 100 * time.Millisecond
+		config.Transport = stallingTransport{Transport: config.Transport, stalled: &stalled}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 2)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// the probes of a round wait on the peer while the clock is advanced, so
+	// their timeout can only run out on the wall clock.
+	stalled.Store(true)
+	before := setup.Now()
+	advanced := make(chan struct{})
+	go func() {
+		setup.Advance(time.Second)
+		close(advanced)
+	}()
+
+	select {
+	case <-advanced:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stalled requests never timed out")
+	}
+	assert.Equal(t, before.Add(time.Second), setup.Now())
+	for _, n := range nodes {
+		assert.NotZero(t, n.Stats().Suspicions, "%s did not suspect its peer", n.Name())
+	}
+}
+
+// a transport whose requests wait until their context is done, while stalled
+// is set.
+type stallingTransport struct {
+	concord.Transport
+	stalled *atomic.Bool
+}
+
+func (t stallingTransport) Dial(addr string) (concord.ClientConn, error) {
+	conn, err := t.Transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return stallingConn{ClientConn: conn, stalled: t.stalled}, nil
+}
+
+type stallingConn struct {
+	concord.ClientConn
+	stalled *atomic.Bool
+}
+
+func (c stallingConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	if c.stalled.Load() {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// listens at addr, accepting connections but never answering on them.
+func blackHole(t *testing.T, addr string) net.Listener {
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	return ln
+}
+
+func TestBlackHoleConnectionEvicted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var metrics []*concord.PrometheusMetrics
+	setup := NewConcordSetup()
+	setup.TCP = true
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 100 * time.Millisecond
+		config.RPCTimeout = 300 * time.Millisecond
+		m := concord.NewPrometheusMetrics()
+		metrics = append(metrics, m)
+		config.Metrics = m
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	// requests to the peer time out rather than fail, and still count as
+	// failures of its connection.
+	hole := nodes[2]
+	require.NoError(t, hole.Stop())
+	defer blackHole(t, hole.Address()).Close()
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		for _, m := range metrics[:2] {
+			rec := httptest.NewRecorder()
+			m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+			assert.Regexp(ct, `concord_connection_cache_evictions_total\{reason="failures"\} [1-9]`, rec.Body.String())
+		}
+	}, 15*time.Second)
+}
+
+func TestPredecessorCrashDetected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	const interval = time.Second
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = interval
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 5)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 20*time.Second)
+
+	crashed := nodes[2]
+	var succ *concord.Concord
+	for _, n := range nodes {
+		if n.Id() == crashed.Successors()[0].Id {
+			succ = n
+		}
+	}
+	require.NotNil(t, succ)
+
+	require.NoError(t, crashed.Stop())
+	start := setup.Now()
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		pred, ok := succ.Predecessor()
+		if ok {
+			assert.NotEqual(ct, crashed.Id(), pred.Id)
+		}
+		assert.NotEqual(ct, crashed.Id(), succ.Range().Start)
+	}, 10*time.Second)
+
+	// the default detector declares the predecessor dead on the third failed
+	// probe, one per stabilization round; before the predecessor of the crashed
+	// node notices the crash and notifies the successor.
+	assert.LessOrEqual(t, setup.Now().Sub(start), 3*interval)
+
+	remaining := []*concord.Concord{nodes[0], nodes[1], nodes[3], nodes[4]}
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, remaining)
+		AssertFullRangeCover(ct, remaining)
+	}, 10*time.Second)
+}
+
+func TestClearedPredecessorKeepsRange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	wholeRing := make(map[string]bool)
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		name := config.Name
+		config.StabilizeInterval = time.Second
+		config.OnRangeChange = func(r concord.Range) {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Start == r.End {
+				wholeRing[name] = true
+			}
+		}
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 5)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 20*time.Second)
+	setup.Advance(10 * time.Second)
+
+	byID := make(map[concord.ID]*concord.Concord)
+	for _, n := range nodes {
+		byID[n.Id()] = n
+	}
+	predecessor := func(n *concord.Concord) *concord.Concord {
+		pred, ok := n.Predecessor()
+		require.True(t, ok)
+		return byID[pred.Id]
+	}
+	node := nodes[0]
+	first := predecessor(node)
+	second := predecessor(first)
+	live := predecessor(second)
+
+	// crash both predecessors of the node, and keep the live node before them
+	// from notifying it; the node adopts the second, finds it dead as well,
+	// and does not know who precedes it.
+	setup.network.SetDeliveryHook(func(from, to, method string) error {
+		if from == live.Address() && to == node.Address() && strings.HasSuffix(method, "/Notify") {
+			return status.Error(codes.Unavailable, "notify dropped")
+		}
+		return nil
+	})
+	mu.Lock()
+	clear(wholeRing)
+	mu.Unlock()
+	require.NoError(t, first.Stop())
+	require.NoError(t, second.Stop())
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		_, ok := node.Predecessor()
+		assert.False(ct, ok, "the predecessor was not cleared")
+	}, 20*time.Second)
+	assert.Equal(t, second.Id(), node.Range().Start, "the range changed while the predecessor is unknown")
+
+	setup.network.SetDeliveryHook(nil)
+	remaining := []*concord.Concord{node, live}
+	for _, n := range nodes {
+		if n != node && n != live && n != first && n != second {
+			remaining = append(remaining, n)
+		}
+	}
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, remaining)
+		AssertFullRangeCover(ct, remaining)
+	}, 20*time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(t, wholeRing, "a node reported the whole ring while others were alive")
+}
+
+func TestSuccessorListProbed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 200 * time.Millisecond
+		config.SuccessorCount = 4
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 8)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		for _, n := range nodes {
+			assert.Equal(ct, concord.Health{Successors: 4, LiveSuccessors: 4, FaultTolerance: 3}, n.Health())
+		}
+	}, 10*time.Second)
+
+	// crash the second and third successors of a node, but not its first.
+	node := nodes[0]
+	byID := make(map[concord.ID]*concord.Concord)
+	for _, n := range nodes {
+		byID[n.Id()] = n
+	}
+	succs := node.Successors()
+	crashed := map[concord.ID]bool{succs[1].Id: true, succs[2].Id: true}
+	for id := range crashed {
+		require.NoError(t, byID[id].Stop())
+	}
+
+	// the crashes are noticed within a round, though the first successor
+	// still answers.
+	setup.Advance(400 * time.Millisecond)
+	health := node.Health()
+	assert.Equal(t, 2, health.LiveSuccessors)
+	assert.Equal(t, 1, health.FaultTolerance)
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		for _, s := range node.Successors() {
+			assert.False(ct, crashed[s.Id], "crashed node %s still a successor", s.Name)
+		}
+		assert.Equal(ct, concord.Health{Successors: 4, LiveSuccessors: 4, FaultTolerance: 3}, node.Health())
+	}, 10*time.Second)
+}
+
+func TestServeOnExternalServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// the server of the application, already serving a service of its own.
+	var served atomic.Int32
+	external := grpc.NewServer(grpc.UnaryInterceptor(
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			served.Add(1)
+			return handler(ctx, req)
+		}))
+	healthpb.RegisterHealthServer(external, health.NewServer())
+
+	var dialed atomic.Int32
+	setup := NewConcordSetup()
+	setup.TCP = true
+	setup.Configure = func(config *concord.Config) {
+		config.Admin = true
+		config.StabilizeInterval = 200 * time.Millisecond
+		config.DialOptions = []grpc.DialOption{grpc.WithUnaryInterceptor(
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				dialed.Add(1)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			})}
+		config.GRPCServerOptions = []grpc.ServerOption{grpc.MaxRecvMsgSize(1 << 20)}
+	}
+
+	nodes := make([]*concord.Concord, 3)
+	for i := range nodes {
+		node, err := setup.CreateNode(t, ctx)
+		require.NoError(t, err)
+		nodes[i] = node
+	}
+	defer setup.StopNodes(ctx, nodes)
+
+	ln, err := net.Listen("tcp", nodes[0].Address())
+	require.NoError(t, err)
+	require.NoError(t, nodes[0].Register(external))
+	go external.Serve(ln)
+	defer external.Stop()
+
+	ln, err = net.Listen("tcp", nodes[1].Address())
+	require.NoError(t, err)
+	require.NoError(t, nodes[1].Serve(ln))
+
+	require.NoError(t, nodes[2].Start())
+
+	err = setup.ConnectCluster(ctx, nodes)
+	require.NoError(t, err, "failed to connect cluster")
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, []byte("test"))
+	}, 10*time.Second)
+
+	assert.Positive(t, served.Load())
+	assert.Positive(t, dialed.Load())
+
+	// the server of the application outlives the node, which looks crashed.
+	conn, err := setup.Dial(nodes[0].Address())
+	require.NoError(t, err)
+	require.NoError(t, nodes[0].Stop())
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = rpc.NewChordServiceClient(conn).GetRing(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	admin := rpc.NewConcordAdminClient(conn)
+	_, err = admin.GetInfo(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = admin.GetStats(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = admin.ForceStabilize(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = admin.FixAllFingers(ctx, &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	setup := NewConcordSetup()
+	setup.Configure = func(config *concord.Config) {
+		config.StabilizeInterval = 200 * time.Millisecond
+	}
+
+	nodes, err := setup.CreateClusterNodes(t, ctx, 3)
+	require.NoError(t, err, "failed to create cluster nodes")
+	defer setup.StopNodes(ctx, nodes)
+
+	require.NoError(t, setup.ConnectCluster(ctx, nodes))
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+	}, 10*time.Second)
+
+	node := nodes[2]
+	others := nodes[:2]
+	assert.Equal(t, concord.StateJoined, node.State())
+
+	var stateErr *concord.StateError
+	err = node.Join(ctx, nodes[0].Address())
+	assert.ErrorIs(t, err, concord.ErrInvalidState)
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, concord.StateError{Op: "join", State: concord.StateJoined}, *stateErr)
+	assert.ErrorIs(t, node.Start(), concord.ErrInvalidState)
+
+	// leave, then join again.
+	require.NoError(t, node.Leave(ctx))
+	assert.Equal(t, concord.StateStarted, node.State())
+	assert.ErrorIs(t, node.Leave(ctx), concord.ErrInvalidState)
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, others)
+		AssertFullRangeCover(ct, others)
+	}, 10*time.Second)
+
+	require.NoError(t, node.Join(ctx, nodes[0].Address()))
+	assert.Equal(t, concord.StateJoined, node.State())
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertFullRangeCover(ct, nodes)
+	}, 10*time.Second)
+
+	// stop, then start and join again.
+	done := node.Done()
+	require.NoError(t, node.Stop())
+	assert.Equal(t, concord.StateStopped, node.State())
+	select {
+	case <-done:
+	default:
+		t.Fatal("Done not closed by Stop")
+	}
+	require.NoError(t, node.Stop())
+	assert.ErrorIs(t, node.Join(ctx, nodes[0].Address()), concord.ErrInvalidState)
+
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, others)
+		AssertFullRangeCover(ct, others)
+	}, 10*time.Second)
+
+	require.NoError(t, node.Start())
+	assert.Equal(t, concord.StateStarted, node.State())
+	assert.NotEqual(t, done, node.Done())
+
+	require.NoError(t, node.Join(ctx, nodes[0].Address()))
+	setup.Eventually(t, func(ct assert.TestingT) {
+		AssertConsistentRing(ct, nodes)
+		AssertConsistentLookupForKey(ct, ctx, nodes, []byte("test"))
+		AssertFullRangeCover(ct, nodes)
+	}, 10*time.Second)
+}
+
+func TestStopCancelsJoin(t *testing.T) {
+	setup := NewConcordSetup()
+	setup.TCP = true
+
+	nodes, err := setup.CreateClusterNodes(t, context.Background(), 1)
+	require.NoError(t, err, "failed to create cluster nodes")
+	node := nodes[0]
+
+	joined := make(chan error, 1)
+	go func() {
+		// no node listens at the seed, so the join would retry forever.
+		joined <- node.Join(context.Background(), "localhost:1")
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, node.Stop())
+
+	select {
+	case err := <-joined:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("join not cancelled by Stop")
+	}
+	<-node.Done()
+	assert.Equal(t, concord.StateStopped, node.State())
 }
@@ -0,0 +1,11 @@
+package testutil
+
+// Recorder implements assert.TestingT, and records whether any assertion
+// failed, so that a check can be retried until it passes.
+type Recorder struct {
+	Failed bool
+}
+
+func (r *Recorder) Errorf(format string, args ...interface{}) {
+	r.Failed = true
+}
@@ -1,10 +1,13 @@
 package unit
 
 import (
+	"context"
+	"net"
 	"testing"
 
 	"github.com/ollelogdahl/concord"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetName(t *testing.T) {
@@ -26,3 +29,71 @@ func TestGetAddress(t *testing.T) {
 
 	assert.Equal(t, instance.Address(), "localhost:1234")
 }
+
+func TestAddressFromListener(t *testing.T) {
+	config := concord.Config{
+		Name:     "foo",
+		BindAddr: "127.0.0.1:0",
+		AdvAddr:  "example.com:{port}",
+	}
+
+	instance := concord.New(config)
+	require.NoError(t, instance.Start())
+	defer instance.Stop()
+
+	host, port, err := net.SplitHostPort(instance.Address())
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", host)
+	assert.NotEqual(t, "0", port)
+	assert.Equal(t, instance.Address(), instance.VirtualNodes()[0].Address)
+}
+
+func TestAddressDefaultsToListener(t *testing.T) {
+	config := concord.Config{
+		Name:     "foo",
+		BindAddr: "127.0.0.1:0",
+	}
+
+	instance := concord.New(config)
+	require.NoError(t, instance.Start())
+	defer instance.Stop()
+
+	host, port, err := net.SplitHostPort(instance.Address())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.NotEqual(t, "0", port)
+}
+
+func TestLookupNotReady(t *testing.T) {
+	config := concord.Config{
+		Name: "foo",
+	}
+
+	instance := concord.New(config)
+
+	_, err := instance.LookupContext(context.Background(), []byte("key"))
+	assert.ErrorIs(t, err, concord.ErrNotReady)
+}
+
+func TestExplicitID(t *testing.T) {
+	id := concord.IDFromUint64(42)
+	config := concord.Config{
+		Name: "foo",
+		ID:   &id,
+	}
+
+	instance := concord.New(config)
+
+	assert.Equal(t, id, instance.Id())
+}
+
+func TestExplicitIDOutsideHashSpace(t *testing.T) {
+	id := concord.IDFromUint64(1 << 40)
+	config := concord.Config{
+		Name:     "foo",
+		ID:       &id,
+		HashBits: 32,
+	}
+
+	assert.Panics(t, func() { concord.New(config) })
+}
@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ollelogdahl/concord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdDetector(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := &concord.ThresholdDetector{Suspect: 2, Dead: 3}
+
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now))
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now))
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerSuspected, d.Status("a", now))
+
+	// an answer resets the count.
+	d.Success("a", now)
+	d.Failure("a", now)
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerSuspected, d.Status("a", now))
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerDead, d.Status("a", now))
+	assert.Equal(t, concord.PeerAlive, d.Status("b", now))
+
+	d.Forget("a")
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now))
+	assert.Equal(t, "threshold suspect=2 dead=3", d.String())
+}
+
+func TestThresholdDetectorDefaults(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := &concord.ThresholdDetector{}
+
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerSuspected, d.Status("a", now))
+	d.Failure("a", now)
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerDead, d.Status("a", now))
+}
+
+func TestPhiAccrualDetector(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := &concord.PhiAccrualDetector{AcceptablePause: time.Second}
+
+	// a peer answering every second, with some variance.
+	for i := range 20 {
+		now = now.Add(time.Second + time.Duration(i%3-1)*100*time.Millisecond)
+		d.Success("a", now)
+	}
+
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now.Add(time.Second)))
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now.Add(1500*time.Millisecond)))
+	assert.Equal(t, concord.PeerSuspected, d.Status("a", now.Add(2200*time.Millisecond)))
+	assert.Equal(t, concord.PeerDead, d.Status("a", now.Add(5*time.Second)))
+
+	// failures do not move the time of the last answer.
+	d.Failure("a", now.Add(time.Second))
+	assert.Equal(t, concord.PeerDead, d.Status("a", now.Add(5*time.Second)))
+
+	d.Success("a", now.Add(5*time.Second))
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now.Add(6*time.Second)))
+
+	d.Forget("a")
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now.Add(time.Hour)))
+}
+
+func TestPhiAccrualDetectorUnknownPeer(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := &concord.PhiAccrualDetector{}
+
+	// silence of a peer never heard from counts from its first failure.
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now))
+	d.Failure("a", now)
+	assert.Equal(t, concord.PeerAlive, d.Status("a", now.Add(time.Second)))
+	assert.Equal(t, concord.PeerDead, d.Status("a", now.Add(time.Minute)))
+}
@@ -0,0 +1,42 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/ollelogdahl/concord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDFromUint64(t *testing.T) {
+	id := concord.IDFromUint64(1234)
+
+	assert.Equal(t, uint64(1234), id.Uint64())
+	assert.Equal(t, "1234", id.String())
+	assert.Equal(t, []byte{0x04, 0xd2}, id.Bytes())
+}
+
+func TestIDFromBytes(t *testing.T) {
+	b := make([]byte, 20)
+	b[0] = 1
+	id := concord.IDFromBytes(b)
+
+	assert.Equal(t, b[:1], id.Bytes()[:1])
+	assert.Len(t, id.Bytes(), 20)
+	assert.Equal(t, "5708990770823839524233143877797980545530986496", id.String())
+}
+
+func TestIDCmp(t *testing.T) {
+	small := concord.IDFromUint64(^uint64(0))
+	big := concord.IDFromBytes([]byte{1, 0, 0, 0, 0, 0, 0, 0, 0})
+
+	assert.Equal(t, -1, small.Cmp(big))
+	assert.Equal(t, 1, big.Cmp(small))
+	assert.Equal(t, 0, big.Cmp(big))
+}
+
+func TestSHA256HashWidth(t *testing.T) {
+	for _, bits := range []uint{8, 64, 160, 256} {
+		id := concord.SHA256Hash(bits)([]byte("key"))
+		assert.LessOrEqual(t, len(id.Bytes())*8, int(bits))
+	}
+}
@@ -0,0 +1,36 @@
+package unit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ollelogdahl/concord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetricsFormat(t *testing.T) {
+	metrics := concord.NewPrometheusMetrics()
+	metrics.ObserveLookup(3*time.Millisecond, 2, nil)
+	metrics.ObserveLookup(2*time.Second, 0, errors.New("failed"))
+	metrics.ObserveForward(`peer "a"`)
+	metrics.SetSuccessors(1, 3)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, body, "# TYPE concord_lookup_duration_seconds histogram\n")
+	assert.Contains(t, body, `concord_lookups_total{result="success"} 1`+"\n")
+	assert.Contains(t, body, `concord_lookups_total{result="failure"} 1`+"\n")
+	assert.Contains(t, body, `concord_lookup_duration_seconds_bucket{le="0.0025"} 0`+"\n")
+	assert.Contains(t, body, `concord_lookup_duration_seconds_bucket{le="0.005"} 1`+"\n")
+	assert.Contains(t, body, `concord_lookup_duration_seconds_bucket{le="+Inf"} 2`+"\n")
+	assert.Contains(t, body, "concord_lookup_duration_seconds_count 2\n")
+	assert.Contains(t, body, `concord_lookup_hops_bucket{le="2"} 1`+"\n")
+	assert.Contains(t, body, "concord_lookup_hops_count 1\n")
+	assert.Contains(t, body, `concord_forwarded_requests_total{peer="peer \"a\""} 1`+"\n")
+	assert.Contains(t, body, `concord_successors{vnode="1"} 3`+"\n")
+}
@@ -0,0 +1,103 @@
+package concord
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// the instrumentation scope of the spans of a node.
+const tracerName = "github.com/ollelogdahl/concord"
+
+// the tracer of a host, and how it carries trace context across requests.
+type tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// starts a span as a child of the span in ctx, if any.
+func (t tracing) start(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// starts the span of a request received by a node, as a child of the span of
+// its sender.
+func (t tracing) startServer(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = t.propagator.Extract(ctx, metadataCarrier(md))
+	return t.start(ctx, spanName(method), trace.SpanKindServer, rpcAttributes(method)...)
+}
+
+// ends span, recording err if it is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// a connection creating a client span for each request, and sending its trace
+// context along in the metadata.
+type tracedConn struct {
+	grpc.ClientConnInterface
+	tracing tracing
+	addr    string
+}
+
+func (c *tracedConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	attrs := append(rpcAttributes(method), attribute.String("server.address", c.addr))
+	ctx, span := c.tracing.start(ctx, spanName(method), trace.SpanKindClient, attrs...)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	c.tracing.propagator.Inject(ctx, metadataCarrier(md))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	endSpan(span, err)
+	return err
+}
+
+// returns the span name of a full method name, as in "concord.ChordService/Notify".
+func spanName(method string) string {
+	return strings.TrimPrefix(method, "/")
+}
+
+func rpcAttributes(method string) []attribute.KeyValue {
+	service, name, _ := strings.Cut(spanName(method), "/")
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", name),
+	}
+}
+
+// carries trace context in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
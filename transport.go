@@ -0,0 +1,126 @@
+package concord
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Carries requests between nodes. A node serves the ChordService of the rpc
+// package (FindSuccessor, ClosestPreceding, GetRing, Notify and Leave) through
+// Listen, and reaches other nodes through Dial.
+//
+// By default, nodes talk gRPC over TCP. A Transport is used by a single node.
+type Transport interface {
+	// Starts serving the services registered by register at addr. Returns the
+	// address actually listened on, which differs from addr if it left the
+	// port for the system to choose.
+	Listen(addr string, register func(grpc.ServiceRegistrar)) (string, error)
+	// Returns a connection to the node listening at addr.
+	Dial(addr string) (ClientConn, error)
+	// Stops serving; the transport may be listened on again afterwards.
+	Close() error
+}
+
+// A connection to a node, as returned by Transport.Dial. Requests sent after
+// Close fail.
+type ClientConn interface {
+	grpc.ClientConnInterface
+	Close() error
+}
+
+// the default transport; gRPC over TCP, optionally with TLS.
+type grpcTransport struct {
+	serverTLS  *tls.Config
+	clientTLS  *tls.Config
+	serverOpts []grpc.ServerOption
+	dialOpts   []grpc.DialOption
+	logger     *slog.Logger
+
+	mu  sync.Mutex
+	srv *grpc.Server
+}
+
+func newGRPCTransport(config *TLSConfig, serverOpts []grpc.ServerOption, dialOpts []grpc.DialOption, logger *slog.Logger) *grpcTransport {
+	t := &grpcTransport{serverOpts: serverOpts, dialOpts: dialOpts, logger: logger}
+	if config != nil {
+		t.serverTLS = config.ServerTLS.Clone()
+		t.clientTLS = config.ClientTLS.Clone()
+	}
+	return t
+}
+
+func (t *grpcTransport) Listen(addr string, register func(grpc.ServiceRegistrar)) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	bound, err := t.serve(ln, register)
+	if err != nil {
+		ln.Close()
+	}
+	return bound, err
+}
+
+// serves the services registered by register on ln, until Close.
+func (t *grpcTransport) serve(ln net.Listener, register func(grpc.ServiceRegistrar)) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.srv != nil {
+		return "", fmt.Errorf("transport already listening")
+	}
+
+	opts := append([]grpc.ServerOption(nil), t.serverOpts...)
+	if t.serverTLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(t.serverTLS)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	register(srv)
+	t.srv = srv
+
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			t.logger.Error("failed to serve", "error", err)
+		}
+	}()
+
+	return ln.Addr().String(), nil
+}
+
+func (t *grpcTransport) Dial(addr string) (ClientConn, error) {
+	var creds credentials.TransportCredentials
+	if t.clientTLS == nil {
+		creds = insecure.NewCredentials()
+	} else {
+		tlsConfig := t.clientTLS.Clone()
+		tlsConfig.ServerName = addr
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, t.dialOpts...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (t *grpcTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.srv != nil {
+		t.srv.Stop()
+		t.srv = nil
+	}
+	return nil
+}